@@ -1,6 +1,8 @@
 package adapter
 
 import (
+	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/df-mc/dragonfly/server"
@@ -10,15 +12,18 @@ import (
 	"github.com/go-gl/mathgl/mgl64"
 
 	"github.com/EinBexiii/dragonfly-wasm/internal/manager"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/chat"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/registry"
 )
 
 type Adapter struct {
-	srv     *server.Server
-	players sync.Map
+	srv      *server.Server
+	players  sync.Map
+	registry *registry.Registry
 }
 
 func NewAdapter(srv *server.Server) *Adapter {
-	return &Adapter{srv: srv}
+	return &Adapter{srv: srv, registry: registry.New()}
 }
 
 func (a *Adapter) TrackPlayer(p *player.Player)   { a.players.Store(p.UUID().String(), p) }
@@ -44,14 +49,14 @@ func (a *Adapter) GetAllPlayers() []manager.PlayerAPI {
 func (a *Adapter) GetWorld(name string) (manager.WorldAPI, bool) {
 	for _, w := range []*world.World{a.srv.World(), a.srv.Nether(), a.srv.End()} {
 		if w != nil && w.Name() == name {
-			return &WorldAdapter{world: w}, true
+			return &WorldAdapter{world: w, registry: a.registry}, true
 		}
 	}
 	return nil, false
 }
 
 func (a *Adapter) GetDefaultWorld() manager.WorldAPI {
-	return &WorldAdapter{world: a.srv.World()}
+	return &WorldAdapter{world: a.srv.World(), registry: a.registry}
 }
 
 func (a *Adapter) BroadcastMessage(msg string) {
@@ -61,6 +66,13 @@ func (a *Adapter) BroadcastMessage(msg string) {
 	})
 }
 
+// BroadcastComponent renders c to its legacy §-encoded form and sends it to
+// every tracked player, letting callers broadcast styled, translatable, or
+// clickable chat rather than plain text.
+func (a *Adapter) BroadcastComponent(c chat.Component) {
+	a.BroadcastMessage(c.Serialize())
+}
+
 type PlayerAdapter struct {
 	player  *player.Player
 	adapter *Adapter
@@ -69,7 +81,12 @@ type PlayerAdapter struct {
 func (p *PlayerAdapter) UUID() string           { return p.player.UUID().String() }
 func (p *PlayerAdapter) Name() string           { return p.player.Name() }
 func (p *PlayerAdapter) SendMessage(msg string) { p.player.Message(msg) }
-func (p *PlayerAdapter) Kick(reason string)     { p.player.Disconnect(reason) }
+
+// SendComponent renders c to its legacy §-encoded form and sends it to p,
+// letting callers send styled, translatable, or clickable chat rather than
+// plain text.
+func (p *PlayerAdapter) SendComponent(c chat.Component) { p.player.Message(c.Serialize()) }
+func (p *PlayerAdapter) Kick(reason string)             { p.player.Disconnect(reason) }
 
 func (p *PlayerAdapter) Teleport(x, y, z float64, _ string) error {
 	p.player.Teleport(mgl64.Vec3{x, y, z})
@@ -101,31 +118,136 @@ func (p *PlayerAdapter) Position() (x, y, z float64) {
 
 func (p *PlayerAdapter) World() manager.WorldAPI {
 	if tx := p.player.Tx(); tx != nil {
-		return &WorldAdapter{world: tx.World()}
+		return &WorldAdapter{world: tx.World(), registry: p.adapter.registry}
 	}
 	return nil
 }
 
 type WorldAdapter struct {
-	world *world.World
+	world    *world.World
+	registry *registry.Registry
+
+	subsMu sync.Mutex
+	subs   map[world.ChunkPos][]func(ChunkDelta)
+}
+
+// ChunkDelta describes a single block replacement reported to a
+// SubscribeChunk callback.
+type ChunkDelta struct {
+	SubchunkY          int
+	X, Y, Z            int
+	OldType, NewType   string
+	OldProps, NewProps map[string]string
+}
+
+// BlockState is a single block read returned from GetBlocks.
+type BlockState struct {
+	X, Y, Z    int
+	Type       string
+	Properties map[string]string
 }
 
 func (w *WorldAdapter) Name() string { return w.world.Name() }
 
+// SubscribeChunk registers cb to be called with every block change reported
+// via NotifyChunkModify for the chunk at pos, and returns a function that
+// removes the subscription. It's a lower-level, Go-native complement to the
+// WASM-facing EventChunkModify dispatched through WorldHandler, meant for
+// embedders that want to mirror or persist world state without round-
+// tripping through a plugin.
+func (w *WorldAdapter) SubscribeChunk(pos world.ChunkPos, cb func(ChunkDelta)) (unsubscribe func()) {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+
+	if w.subs == nil {
+		w.subs = make(map[world.ChunkPos][]func(ChunkDelta))
+	}
+	w.subs[pos] = append(w.subs[pos], cb)
+	index := len(w.subs[pos]) - 1
+
+	return func() {
+		w.subsMu.Lock()
+		defer w.subsMu.Unlock()
+		subs := w.subs[pos]
+		if index >= len(subs) {
+			return
+		}
+		w.subs[pos] = append(subs[:index], subs[index+1:]...)
+	}
+}
+
+// NotifyChunkModify invokes every callback subscribed to pos with delta. The
+// caller (typically a WorldHandler observing the same transaction) is
+// responsible for detecting the underlying block change.
+func (w *WorldAdapter) NotifyChunkModify(pos world.ChunkPos, delta ChunkDelta) {
+	w.subsMu.Lock()
+	subs := append([]func(ChunkDelta){}, w.subs[pos]...)
+	w.subsMu.Unlock()
+
+	for _, cb := range subs {
+		cb(delta)
+	}
+}
+
+// regionBounds converts region's float64 corners (cube.BBox wraps
+// mgl64.Vec3) into the inclusive integer block bounds GetBlocks loops over.
+func regionBounds(region cube.BBox) (minX, minY, minZ, maxX, maxY, maxZ int) {
+	min, max := region.Min(), region.Max()
+	return int(min.X()), int(min.Y()), int(min.Z()), int(max.X()), int(max.Y()), int(max.Z())
+}
+
+// GetBlocks reads every block within region in a single world transaction,
+// for bulk world-capture use cases where a per-block RPC across the WASM
+// boundary is prohibitively slow.
+func (w *WorldAdapter) GetBlocks(region cube.BBox) []BlockState {
+	minX, minY, minZ, maxX, maxY, maxZ := regionBounds(region)
+	states := make([]BlockState, 0)
+
+	w.world.Exec(func(tx *world.Tx) {
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				for z := minZ; z <= maxZ; z++ {
+					pos := cube.Pos{x, y, z}
+					b := tx.Block(pos)
+					blockType := "air"
+					var properties map[string]string
+					if b != nil {
+						var props map[string]any
+						blockType, props = b.EncodeBlock()
+						properties = stringifyProperties(props)
+					}
+					if properties == nil {
+						properties = make(map[string]string)
+					}
+					states = append(states, BlockState{X: x, Y: y, Z: z, Type: blockType, Properties: properties})
+				}
+			}
+		}
+	})
+	return states
+}
+
 func (w *WorldAdapter) GetBlock(x, y, z int) (string, map[string]string) {
 	var blockType string
+	var properties map[string]string
 	w.world.Exec(func(tx *world.Tx) {
-		if b := tx.Block(cube.Pos{x, y, z}); b != nil {
-			blockType, _ = b.EncodeBlock()
-		} else {
+		b := tx.Block(cube.Pos{x, y, z})
+		if b == nil {
 			blockType = "air"
+			return
 		}
+		var props map[string]any
+		blockType, props = b.EncodeBlock()
+		properties = stringifyProperties(props)
 	})
-	return blockType, make(map[string]string)
+	if properties == nil {
+		properties = make(map[string]string)
+	}
+	return blockType, properties
 }
 
-func (w *WorldAdapter) SetBlock(x, y, z int, blockType string, _ map[string]string) error {
-	block, ok := blockByName(blockType)
+func (w *WorldAdapter) SetBlock(x, y, z int, blockType string, properties map[string]string) error {
+	block, ok := w.registry.BlockByName(blockType, typedProperties(properties))
 	if !ok {
 		return nil
 	}
@@ -135,4 +257,33 @@ func (w *WorldAdapter) SetBlock(x, y, z int, blockType string, _ map[string]stri
 	return nil
 }
 
-func blockByName(_ string) (world.Block, bool) { return nil, false }
+// stringifyProperties renders a block's typed NBT state properties as
+// strings for the wire format.
+func stringifyProperties(properties map[string]any) map[string]string {
+	out := make(map[string]string, len(properties))
+	for k, v := range properties {
+		out[k] = fmt.Sprintf("%v", v)
+	}
+	return out
+}
+
+// typedProperties reconstructs the typed NBT state values the registry
+// indexed blocks under from the wire's flat string map, since bool and
+// integer state properties (e.g. upside_down_bit, facing_direction) are
+// not stored as strings internally.
+func typedProperties(properties map[string]string) map[string]any {
+	out := make(map[string]any, len(properties))
+	for k, v := range properties {
+		switch {
+		case v == "true" || v == "false":
+			out[k] = v == "true"
+		default:
+			if n, err := strconv.ParseInt(v, 10, 32); err == nil {
+				out[k] = int32(n)
+				continue
+			}
+			out[k] = v
+		}
+	}
+	return out
+}
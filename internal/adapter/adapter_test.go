@@ -0,0 +1,20 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+)
+
+func TestRegionBounds(t *testing.T) {
+	region := cube.Box(-2, 0, 5, 3, 10, 1)
+
+	minX, minY, minZ, maxX, maxY, maxZ := regionBounds(region)
+
+	if minX != -2 || minY != 0 || minZ != 1 {
+		t.Fatalf("min = (%d, %d, %d), want (-2, 0, 1)", minX, minY, minZ)
+	}
+	if maxX != 3 || maxY != 10 || maxZ != 5 {
+		t.Fatalf("max = (%d, %d, %d), want (3, 10, 5)", maxX, maxY, maxZ)
+	}
+}
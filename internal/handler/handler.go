@@ -2,7 +2,6 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/df-mc/dragonfly/server/block/cube"
@@ -13,80 +12,67 @@ import (
 	"github.com/go-gl/mathgl/mgl64"
 	"go.uber.org/zap"
 
+	"github.com/EinBexiii/dragonfly-wasm/pkg/chat"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/events"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events/proto"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/registry"
 )
 
 type PlayerHandler struct {
 	player.NopHandler
 	dispatcher *events.Dispatcher
+	registry   *registry.Registry
 	logger     *zap.Logger
 	ctx        context.Context
 }
 
-func NewPlayerHandler(dispatcher *events.Dispatcher, logger *zap.Logger) *PlayerHandler {
+func NewPlayerHandler(dispatcher *events.Dispatcher, reg *registry.Registry, logger *zap.Logger) *PlayerHandler {
 	return &PlayerHandler{
 		dispatcher: dispatcher,
+		registry:   reg,
 		logger:     logger.Named("player-handler"),
 		ctx:        context.Background(),
 	}
 }
 
-type jsonMessage struct{ data []byte }
-
-func (m *jsonMessage) Reset()         {}
-func (m *jsonMessage) String() string { return string(m.data) }
-func (m *jsonMessage) ProtoMessage()  {}
-
-func playerToMap(p *player.Player) map[string]any {
+func playerToProto(p *player.Player) proto.Player {
 	pos, rot := p.Position(), p.Rotation()
 	var worldName string
 	if tx := p.Tx(); tx != nil {
 		worldName = tx.World().Name()
 	}
-	return map[string]any{
-		"uuid":       p.UUID().String(),
-		"name":       p.Name(),
-		"xuid":       p.XUID(),
-		"position":   map[string]float64{"x": pos.X(), "y": pos.Y(), "z": pos.Z()},
-		"yaw":        rot.Yaw(),
-		"pitch":      rot.Pitch(),
-		"world_name": worldName,
-		"health":     p.Health(),
-		"max_health": p.MaxHealth(),
+	return proto.Player{
+		UUID:      p.UUID().String(),
+		Name:      p.Name(),
+		XUID:      p.XUID(),
+		Position:  vec3ToProto(pos),
+		Yaw:       float32(rot.Yaw()),
+		Pitch:     float32(rot.Pitch()),
+		WorldName: worldName,
+		Health:    p.Health(),
+		MaxHealth: p.MaxHealth(),
 	}
 }
 
-func vec3ToMap(v mgl64.Vec3) map[string]float64 {
-	return map[string]float64{"x": v.X(), "y": v.Y(), "z": v.Z()}
+func vec3ToProto(v mgl64.Vec3) proto.Vec3 {
+	return proto.Vec3{X: v.X(), Y: v.Y(), Z: v.Z()}
 }
 
-func blockPosToMap(pos cube.Pos) map[string]int {
-	return map[string]int{"x": pos.X(), "y": pos.Y(), "z": pos.Z()}
+func blockPosToProto(pos cube.Pos) proto.BlockPos {
+	return proto.BlockPos{X: int32(pos.X()), Y: int32(pos.Y()), Z: int32(pos.Z())}
 }
 
-func itemToMap(stack item.Stack) map[string]any {
+func (h *PlayerHandler) itemToProto(stack item.Stack) proto.Item {
 	itemType := "air"
 	if !stack.Empty() {
-		itemType = itemTypeName(stack.Item())
-	}
-	return map[string]any{"item_type": itemType, "count": stack.Count()}
-}
-
-func itemTypeName(i world.Item) string {
-	if i == nil {
-		return "air"
-	}
-	if enc, ok := i.(world.NBTer); ok {
-		if name, ok := enc.EncodeNBT()["name"].(string); ok {
-			return name
-		}
+		itemType = h.registry.NameForItem(stack.Item())
 	}
-	return "unknown"
+	return proto.Item{Type: itemType, Count: int32(stack.Count())}
 }
 
-func blockToMap(b world.Block, pos cube.Pos) map[string]any {
-	return map[string]any{"block_type": blockTypeName(b), "position": blockPosToMap(pos)}
+func blockToProto(b world.Block, pos cube.Pos) proto.Block {
+	return proto.Block{Type: blockTypeName(b), Position: blockPosToProto(pos)}
 }
 
 func blockTypeName(b world.Block) string {
@@ -97,141 +83,167 @@ func blockTypeName(b world.Block) string {
 	return name
 }
 
-func entityToMap(e world.Entity) map[string]any {
+func entityToProto(e world.Entity) proto.Entity {
 	pos, rot := e.Position(), e.Rotation()
-	data := map[string]any{
-		"entity_type": "entity",
-		"position":    vec3ToMap(pos),
-		"yaw":         rot.Yaw(),
-		"pitch":       rot.Pitch(),
+	ent := proto.Entity{
+		Type:  "entity",
+		Pos:   vec3ToProto(pos),
+		Yaw:   float32(rot.Yaw()),
+		Pitch: float32(rot.Pitch()),
 	}
 	if p, ok := e.(*player.Player); ok {
-		data["uuid"] = p.UUID().String()
-		data["entity_type"] = "player"
+		ent.UUID = p.UUID().String()
+		ent.Type = "player"
 	}
-	return data
+	return ent
 }
 
-func (h *PlayerHandler) dispatchEvent(eventType plugin.EventType, data map[string]any) (bool, map[string]string) {
+// dispatchEvent encodes body as the versioned payload for eventType and
+// dispatches it, returning whether a handler cancelled the event and any
+// patch it staged against the payload.
+func (h *PlayerHandler) dispatchEvent(eventType plugin.EventType, body any) (bool, *proto.Patch) {
 	if !h.dispatcher.HasSubscribers(eventType) {
 		return false, nil
 	}
 
-	jsonData, err := json.Marshal(data)
+	envelope, err := proto.Encode(string(eventType), body)
 	if err != nil {
-		h.logger.Error("marshal event data", zap.Error(err))
+		h.logger.Error("encode event payload", zap.Error(err))
 		return false, nil
 	}
 
-	result, err := h.dispatcher.Dispatch(h.ctx, eventType, &jsonMessage{data: jsonData})
+	result, err := h.dispatcher.Dispatch(h.ctx, eventType, envelope)
 	if err != nil {
 		h.logger.Error("dispatch event", zap.String("event", string(eventType)), zap.Error(err))
 		return false, nil
 	}
 
 	if result != nil {
-		return result.Cancelled, result.Modifications
+		return result.Cancelled, result.Patch
 	}
 	return false, nil
 }
 
+// dispatchEventFireAndForget is dispatchEvent's queued counterpart for
+// events that fire far more often than plugins can usefully act on them
+// (movement, jumping). It never blocks the caller and carries no
+// cancel/patch result, since a subscriber may not even have run by the
+// time this returns.
+func (h *PlayerHandler) dispatchEventFireAndForget(eventType plugin.EventType, body any) {
+	if !h.dispatcher.HasSubscribers(eventType) {
+		return
+	}
+
+	envelope, err := proto.Encode(string(eventType), body)
+	if err != nil {
+		h.logger.Error("encode event payload", zap.Error(err))
+		return
+	}
+
+	h.dispatcher.DispatchFireAndForget(h.ctx, eventType, envelope)
+}
+
 func (h *PlayerHandler) HandleChat(ctx *player.Context, message *string) {
-	cancelled, mods := h.dispatchEvent(plugin.EventPlayerChat, map[string]any{
-		"player":  playerToMap(ctx.Val()),
-		"message": *message,
+	cancelled, patch := h.dispatchEvent(plugin.EventPlayerChat, proto.PlayerChat{
+		Player:    playerToProto(ctx.Val()),
+		Message:   *message,
+		Component: chat.ParseLegacy(*message),
 	})
 	if cancelled {
 		ctx.Cancel()
 	}
-	if newMsg, ok := mods["message"]; ok {
+	if newMsg, ok := patch.GetString("message"); ok {
 		*message = newMsg
 	}
 }
 
+// HandleMove fires at up to tick rate per player, so it is queued rather
+// than dispatched synchronously: a plugin can observe movement but, unlike
+// HandleTeleport, can no longer cancel it. A queued coalescing burst of
+// positions collapsing to "the latest one" means a cancel decided on stale
+// data would be wrong as often as it was right.
 func (h *PlayerHandler) HandleMove(ctx *player.Context, newPos mgl64.Vec3, newRot cube.Rotation) {
-	cancelled, _ := h.dispatchEvent(plugin.EventPlayerMove, map[string]any{
-		"new_position": vec3ToMap(newPos),
-		"new_yaw":      newRot.Yaw(),
-		"new_pitch":    newRot.Pitch(),
+	h.dispatchEventFireAndForget(plugin.EventPlayerMove, proto.PlayerMove{
+		NewPosition: vec3ToProto(newPos),
+		NewYaw:      float32(newRot.Yaw()),
+		NewPitch:    float32(newRot.Pitch()),
 	})
-	if cancelled {
-		ctx.Cancel()
-	}
 }
 
 func (h *PlayerHandler) HandleTeleport(ctx *player.Context, pos mgl64.Vec3) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerTeleport, map[string]any{"to": vec3ToMap(pos)}); cancelled {
+	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerTeleport, proto.PlayerTeleport{To: vec3ToProto(pos)}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleJump(p *player.Player) {
-	h.dispatchEvent(plugin.EventPlayerJump, map[string]any{"player": playerToMap(p)})
+	h.dispatchEventFireAndForget(plugin.EventPlayerJump, proto.PlayerPresence{Player: playerToProto(p)})
 }
 
 func (h *PlayerHandler) HandleToggleSprint(ctx *player.Context, after bool) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerSprint, map[string]any{
-		"player": playerToMap(ctx.Val()), "sprinting": after,
+	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerSprint, proto.PlayerPresence{
+		Player: playerToProto(ctx.Val()), Value: after,
 	}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleToggleSneak(ctx *player.Context, after bool) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerSneak, map[string]any{
-		"player": playerToMap(ctx.Val()), "sneaking": after,
+	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerSneak, proto.PlayerPresence{
+		Player: playerToProto(ctx.Val()), Value: after,
 	}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleDeath(p *player.Player, src world.DamageSource, keepInv *bool) {
-	_, mods := h.dispatchEvent(plugin.EventPlayerDeath, map[string]any{
-		"player":         playerToMap(p),
-		"damage_source":  damageSourceToString(src),
-		"keep_inventory": *keepInv,
+	_, patch := h.dispatchEvent(plugin.EventPlayerDeath, proto.PlayerDeath{
+		Player:        playerToProto(p),
+		DamageSource:  damageSourceToString(src),
+		KeepInventory: *keepInv,
 	})
-	if keep, ok := mods["keep_inventory"]; ok {
-		*keepInv = keep == "true"
+	if keep, ok := patch.GetBool("keep_inventory"); ok {
+		*keepInv = keep
 	}
 }
 
 func (h *PlayerHandler) HandleRespawn(p *player.Player, pos *mgl64.Vec3, w **world.World) {
-	h.dispatchEvent(plugin.EventPlayerRespawn, map[string]any{
-		"player":         playerToMap(p),
-		"spawn_position": vec3ToMap(*pos),
+	h.dispatchEvent(plugin.EventPlayerRespawn, proto.PlayerRespawn{
+		Player:        playerToProto(p),
+		SpawnPosition: vec3ToProto(*pos),
 	})
 }
 
 func (h *PlayerHandler) HandleHurt(ctx *player.Context, damage *float64, immune bool, attackImmunity *time.Duration, src world.DamageSource) {
-	eventData := map[string]any{
-		"player":        playerToMap(ctx.Val()),
-		"damage":        *damage,
-		"immune":        immune,
-		"damage_source": damageSourceToString(src),
+	body := proto.PlayerHurt{
+		Player:       playerToProto(ctx.Val()),
+		Damage:       *damage,
+		Immune:       immune,
+		DamageSource: damageSourceToString(src),
 	}
 	if attacker, ok := src.(entity.AttackDamageSource); ok && attacker.Attacker != nil {
-		eventData["attacker"] = entityToMap(attacker.Attacker)
+		ent := entityToProto(attacker.Attacker)
+		body.Attacker = &ent
 	}
-	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerHurt, eventData); cancelled {
+	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerHurt, body); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleHeal(ctx *player.Context, health *float64, src world.HealingSource) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerHeal, map[string]any{
-		"player":      playerToMap(ctx.Val()),
-		"amount":      *health,
-		"heal_source": healingSourceToString(src),
+	if cancelled, _ := h.dispatchEvent(plugin.EventPlayerHeal, proto.PlayerHeal{
+		Player:     playerToProto(ctx.Val()),
+		Amount:     *health,
+		HealSource: healingSourceToString(src),
 	}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleBlockBreak(ctx *player.Context, pos cube.Pos, drops *[]item.Stack, xp *int) {
-	dropsData := make([]map[string]any, len(*drops))
+	dropsData := make([]proto.Item, len(*drops))
 	for i, stack := range *drops {
-		dropsData[i] = itemToMap(stack)
+		dropsData[i] = h.itemToProto(stack)
 	}
 
 	var blockType string
@@ -239,46 +251,46 @@ func (h *PlayerHandler) HandleBlockBreak(ctx *player.Context, pos cube.Pos, drop
 		blockType = blockTypeName(tx.Block(pos))
 	}
 
-	if cancelled, _ := h.dispatchEvent(plugin.EventBlockBreak, map[string]any{
-		"player":     playerToMap(ctx.Val()),
-		"block":      map[string]any{"block_type": blockType, "position": blockPosToMap(pos)},
-		"drops":      dropsData,
-		"experience": *xp,
+	if cancelled, _ := h.dispatchEvent(plugin.EventBlockBreak, proto.BlockBreak{
+		Player:     playerToProto(ctx.Val()),
+		Block:      proto.Block{Type: blockType, Position: blockPosToProto(pos)},
+		Drops:      dropsData,
+		Experience: int32(*xp),
 	}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleBlockPlace(ctx *player.Context, pos cube.Pos, b world.Block) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventBlockPlace, map[string]any{
-		"player": playerToMap(ctx.Val()),
-		"block":  blockToMap(b, pos),
+	if cancelled, _ := h.dispatchEvent(plugin.EventBlockPlace, proto.BlockPlace{
+		Player: playerToProto(ctx.Val()),
+		Block:  blockToProto(b, pos),
 	}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleItemUse(ctx *player.Context) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventItemUse, map[string]any{"player": playerToMap(ctx.Val())}); cancelled {
+	if cancelled, _ := h.dispatchEvent(plugin.EventItemUse, proto.ItemUse{Player: playerToProto(ctx.Val())}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleItemUseOnBlock(ctx *player.Context, pos cube.Pos, face cube.Face, clickPos mgl64.Vec3) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventItemUseOnBlock, map[string]any{
-		"player":         playerToMap(ctx.Val()),
-		"position":       blockPosToMap(pos),
-		"face":           int(face),
-		"click_position": vec3ToMap(clickPos),
+	if cancelled, _ := h.dispatchEvent(plugin.EventItemUseOnBlock, proto.ItemUseOnBlock{
+		Player:        playerToProto(ctx.Val()),
+		Position:      blockPosToProto(pos),
+		Face:          int32(face),
+		ClickPosition: vec3ToProto(clickPos),
 	}); cancelled {
 		ctx.Cancel()
 	}
 }
 
 func (h *PlayerHandler) HandleItemUseOnEntity(ctx *player.Context, e world.Entity) {
-	if cancelled, _ := h.dispatchEvent(plugin.EventItemUseOnEntity, map[string]any{
-		"player": playerToMap(ctx.Val()),
-		"target": entityToMap(e),
+	if cancelled, _ := h.dispatchEvent(plugin.EventItemUseOnEntity, proto.ItemUseOnEntity{
+		Player: playerToProto(ctx.Val()),
+		Target: entityToProto(e),
 	}); cancelled {
 		ctx.Cancel()
 	}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events/proto"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+// PacketHandler lets plugins subscribe to raw gophertunnel packets crossing
+// a connection, below the higher-level player.Handler callbacks in
+// handler.go. It's registered alongside a PlayerHandler for the same
+// connection wherever packets are read from or written to the wire.
+type PacketHandler struct {
+	dispatcher *events.Dispatcher
+	logger     *zap.Logger
+	ctx        context.Context
+}
+
+func NewPacketHandler(dispatcher *events.Dispatcher, logger *zap.Logger) *PacketHandler {
+	return &PacketHandler{
+		dispatcher: dispatcher,
+		logger:     logger.Named("packet-handler"),
+		ctx:        context.Background(),
+	}
+}
+
+// HandleReceive is called with a packet decoded from the client, before it
+// reaches dragonfly's own packet handling. A true return means a plugin
+// cancelled the packet and it should be dropped.
+func (h *PacketHandler) HandleReceive(pk packet.Packet) (cancelled bool) {
+	return h.dispatch(plugin.EventPacketReceive, proto.PacketReceive, pk)
+}
+
+// HandleSend is called with a packet about to be encoded and sent to the
+// client. A true return means a plugin cancelled the packet and it should
+// not be sent.
+func (h *PacketHandler) HandleSend(pk packet.Packet) (cancelled bool) {
+	return h.dispatch(plugin.EventPacketSend, proto.PacketSend, pk)
+}
+
+func (h *PacketHandler) dispatch(eventType plugin.EventType, dir proto.PacketDirection, pk packet.Packet) bool {
+	if !h.dispatcher.HasSubscribers(eventType) {
+		return false
+	}
+
+	envelope, err := proto.Encode(string(eventType), proto.Packet{
+		Direction:  dir,
+		PacketID:   uint32(pk.ID()),
+		PacketName: fmt.Sprintf("%T", pk),
+	})
+	if err != nil {
+		h.logger.Error("encode packet event", zap.Error(err))
+		return false
+	}
+
+	result, err := h.dispatcher.Dispatch(h.ctx, eventType, envelope)
+	if err != nil {
+		h.logger.Error("dispatch packet event", zap.String("event", string(eventType)), zap.Error(err))
+		return false
+	}
+	return result != nil && result.Cancelled
+}
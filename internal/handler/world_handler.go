@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events/proto"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/registry"
+)
+
+// BlockChange describes a single block replacement to report through
+// HandleChunkModify, before it's reduced to the wire's proto.BlockDelta.
+type BlockChange struct {
+	SubchunkY int32
+	Position  cube.Pos
+	Old       world.Block
+	New       world.Block
+	NBT       map[string]any
+}
+
+// WorldHandler lets plugins subscribe to chunk-scoped world events, rather
+// than polling WorldAdapter.GetBlock per coordinate. It's registered
+// alongside a PlayerHandler for the world(s) a caller wants to observe.
+type WorldHandler struct {
+	dispatcher *events.Dispatcher
+	registry   *registry.Registry
+	logger     *zap.Logger
+	ctx        context.Context
+}
+
+func NewWorldHandler(dispatcher *events.Dispatcher, reg *registry.Registry, logger *zap.Logger) *WorldHandler {
+	return &WorldHandler{
+		dispatcher: dispatcher,
+		registry:   reg,
+		logger:     logger.Named("world-handler"),
+		ctx:        context.Background(),
+	}
+}
+
+// HandleChunkLoad reports a chunk entering memory for dimension.
+func (h *WorldHandler) HandleChunkLoad(dimension string, pos world.ChunkPos) {
+	h.dispatch(plugin.EventChunkLoad, proto.ChunkLoad{
+		Dimension: dimension,
+		Chunk:     chunkPosToProto(pos),
+	})
+}
+
+// HandleChunkUnload reports a chunk leaving memory for dimension.
+func (h *WorldHandler) HandleChunkUnload(dimension string, pos world.ChunkPos) {
+	h.dispatch(plugin.EventChunkUnload, proto.ChunkUnload{
+		Dimension: dimension,
+		Chunk:     chunkPosToProto(pos),
+	})
+}
+
+// HandleChunkModify reports a batch of block changes within a chunk, letting
+// subscribers mirror or persist world state from the delta list instead of
+// re-reading every coordinate. It returns whether a subscriber cancelled the
+// event.
+func (h *WorldHandler) HandleChunkModify(dimension string, pos world.ChunkPos, changes []BlockChange) bool {
+	deltas := make([]proto.BlockDelta, len(changes))
+	for i, c := range changes {
+		deltas[i] = proto.BlockDelta{
+			SubchunkY: c.SubchunkY,
+			Position:  blockPosToProto(c.Position),
+			OldBlock:  blockToProto(c.Old, c.Position),
+			NewBlock:  blockToProto(c.New, c.Position),
+			NBT:       stringifyProperties(c.NBT),
+		}
+	}
+
+	cancelled, _ := h.dispatch(plugin.EventChunkModify, proto.ChunkModify{
+		Dimension: dimension,
+		Chunk:     chunkPosToProto(pos),
+		Deltas:    deltas,
+	})
+	return cancelled
+}
+
+func (h *WorldHandler) dispatch(eventType plugin.EventType, body any) (bool, *proto.Patch) {
+	if !h.dispatcher.HasSubscribers(eventType) {
+		return false, nil
+	}
+
+	envelope, err := proto.Encode(string(eventType), body)
+	if err != nil {
+		h.logger.Error("encode world event payload", zap.Error(err))
+		return false, nil
+	}
+
+	result, err := h.dispatcher.Dispatch(h.ctx, eventType, envelope)
+	if err != nil {
+		h.logger.Error("dispatch world event", zap.String("event", string(eventType)), zap.Error(err))
+		return false, nil
+	}
+
+	if result != nil {
+		return result.Cancelled, result.Patch
+	}
+	return false, nil
+}
+
+func chunkPosToProto(pos world.ChunkPos) proto.ChunkPos {
+	return proto.ChunkPos{X: pos[0], Z: pos[1]}
+}
+
+// stringifyProperties renders a block entity's NBT as strings for the wire
+// format, mirroring adapter.stringifyProperties.
+func stringifyProperties(properties map[string]any) map[string]string {
+	if properties == nil {
+		return nil
+	}
+	out := make(map[string]string, len(properties))
+	for k, v := range properties {
+		out[k] = fmt.Sprintf("%v", v)
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/config"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+// ApplyConfig swaps in next as the active Config and reacts to what
+// changed relative to the config it replaces: a currently-loaded plugin
+// that's newly disabled (per next.IsPluginEnabled) is disabled, one that's
+// newly enabled is re-enabled; a plugin named in next.EnabledPlugins that
+// isn't loaded at all yet is picked up via LoadAll. DefaultLimits/
+// GlobalLimits changes get no extra action here: createEventHandler calls
+// Manager.cfg().GetEffectiveLimits on every dispatch, so a changed
+// MaxExecutionMs takes effect on the very next event, but the memory cap is
+// applied only when an instance is created, so an already-loaded plugin
+// keeps its old MaxMemoryMB until it is rebuilt or reloaded.
+//
+// It's meant to be driven by a config.Watcher's Changes() channel, but
+// takes next directly so it composes with any other source of config
+// reloads.
+func (m *Manager) ApplyConfig(ctx context.Context, next *config.Config) error {
+	m.config.Store(next)
+
+	m.mu.RLock()
+	ids := make([]string, 0, len(m.plugins))
+	for id := range m.plugins {
+		ids = append(ids, id)
+	}
+	m.mu.RUnlock()
+
+	for _, id := range ids {
+		loaded, ok := m.GetPlugin(id)
+		if !ok {
+			continue
+		}
+
+		switch wantEnabled := next.IsPluginEnabled(id); {
+		case wantEnabled && loaded.Info.State != plugin.StateEnabled:
+			if err := m.EnablePlugin(id); err != nil {
+				m.logger.Warn("config reload: failed to enable plugin", zap.String("id", id), zap.Error(err))
+			}
+		case !wantEnabled && loaded.Info.State == plugin.StateEnabled:
+			if err := m.DisablePlugin(id); err != nil {
+				m.logger.Warn("config reload: failed to disable plugin", zap.String("id", id), zap.Error(err))
+			}
+		}
+	}
+
+	needsLoadAll := false
+	for _, id := range next.EnabledPlugins {
+		if _, ok := m.GetPlugin(id); !ok {
+			needsLoadAll = true
+			break
+		}
+	}
+	if needsLoadAll {
+		if err := m.LoadAll(ctx); err != nil {
+			m.logger.Warn("config reload: failed to load newly-enabled plugins", zap.Error(err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"sync"
+	"time"
+)
+
+// deadline gives a LoadedPlugin a channel host functions can select against
+// to notice "this call's handler timeout has fired" without polling,
+// modelled on gVisor/netstack gonet's deadlineTimer: a timer that, on
+// expiry, closes the current readCancelCh and swaps in a fresh one so a
+// later setDeadline doesn't cancel callers still waiting on the old one.
+//
+// This is distinct from pkg/host's pluginDeadline: that one guards
+// FunctionProvider's own runWithDeadline wrapper around a single host call;
+// this one is shared by every host function a single plugin instance makes
+// during one handle_event dispatch, so createEventHandler's timeout can
+// reach all of them at once.
+type deadline struct {
+	mu           sync.Mutex
+	timer        *time.Timer
+	readCancelCh chan struct{}
+}
+
+func newDeadline() *deadline {
+	return &deadline{readCancelCh: make(chan struct{})}
+}
+
+// setDeadline arms d to fire after dur, cancelling any previously armed
+// timer first. A non-positive dur disarms d without firing it.
+func (d *deadline) setDeadline(dur time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if dur <= 0 {
+		return
+	}
+	d.timer = time.AfterFunc(dur, d.resetDeadline)
+}
+
+// resetDeadline closes the current readCancelCh, waking every goroutine
+// selecting on channel(), then installs a fresh one so the next setDeadline
+// starts from a clean slate.
+func (d *deadline) resetDeadline() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	close(d.readCancelCh)
+	d.readCancelCh = make(chan struct{})
+}
+
+// channel returns d's current cancellation channel. Callers must read it
+// once before entering a select, since a concurrent resetDeadline replaces
+// the field - selecting on a stale snapshot is exactly what's wanted, since
+// that's the channel that will actually close.
+func (d *deadline) channel() chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.readCancelCh
+}
@@ -0,0 +1,181 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	extism "github.com/extism/go-sdk"
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+// devWatchPollInterval and devWatchDebounce control how WatchDevPlugin
+// notices and coalesces WASM file writes. fsnotify isn't vendored in this
+// module (and this sandbox has no network access to add it), so this polls
+// os.Stat's ModTime as a stand-in for an inotify/kqueue watch; swap the body
+// of runDevWatch for a real fsnotify.Watcher once that dependency is
+// available, without touching WatchDevPlugin's contract.
+const devWatchPollInterval = 250 * time.Millisecond
+const devWatchDebounce = 250 * time.Millisecond
+
+// WatchDevPlugin starts polling id's wasmPath for changes and hot-reloads
+// the plugin in place whenever a write settles. It only runs when
+// config.DevMode is set, since reloading on every write is only ever
+// appropriate on a developer's own machine. Calling it again for an
+// already-watched id restarts the watch on its current WASMPath.
+func (m *Manager) WatchDevPlugin(id string) error {
+	if !m.cfg().DevMode {
+		return errors.New("dev mode is not enabled")
+	}
+
+	loaded, exists := m.GetPlugin(id)
+	if !exists {
+		return fmt.Errorf("plugin %s not found", id)
+	}
+	wasmPath := loaded.Info.WASMPath
+
+	m.devWatchersMu.Lock()
+	if m.devWatchers == nil {
+		m.devWatchers = make(map[string]context.CancelFunc)
+	}
+	if cancel, watching := m.devWatchers[id]; watching {
+		cancel()
+	}
+	watchCtx, cancel := context.WithCancel(m.ctx)
+	m.devWatchers[id] = cancel
+	m.devWatchersMu.Unlock()
+
+	go m.runDevWatch(watchCtx, id, wasmPath)
+
+	m.logger.Info("watching plugin for dev hot-reload", zap.String("id", id), zap.String("path", wasmPath))
+	return nil
+}
+
+func (m *Manager) runDevWatch(ctx context.Context, id, wasmPath string) {
+	ticker := time.NewTicker(devWatchPollInterval)
+	defer ticker.Stop()
+
+	lastMod, _ := fileModTime(wasmPath)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mod, err := fileModTime(wasmPath)
+			if err != nil || mod.Equal(lastMod) {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(devWatchDebounce):
+			}
+
+			settled, err := fileModTime(wasmPath)
+			if err != nil || !settled.Equal(mod) {
+				// Still being written; pick up the final state on a later tick.
+				continue
+			}
+			lastMod = settled
+
+			if err := m.reloadDevPlugin(id); err != nil {
+				m.logger.Error("dev plugin reload failed", zap.String("id", id), zap.Error(err))
+			}
+		}
+	}
+}
+
+func fileModTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// reloadDevPlugin rebuilds loaded's Extism instance from its current
+// plugin.toml and wasmPath in place. It deliberately doesn't call the
+// public DisablePlugin/UnloadPlugin/loadPlugin/EnablePlugin sequence the
+// request describes verbatim - those each take loaded.mu and m.mu
+// themselves, so calling them from inside an already-held loaded.mu would
+// deadlock. Instead it performs the same steps (disable, swap instance,
+// re-register subscriptions from the freshly re-read manifest, re-enable)
+// under a single critical section, which is what "under the same
+// LoadedPlugin.mu" amounts to.
+func (m *Manager) reloadDevPlugin(id string) error {
+	loaded, exists := m.GetPlugin(id)
+	if !exists {
+		return fmt.Errorf("plugin %s not found", id)
+	}
+
+	loaded.mu.Lock()
+	defer loaded.mu.Unlock()
+
+	pluginPath := filepath.Dir(loaded.Info.WASMPath)
+	manifestPath := filepath.Join(pluginPath, "plugin.toml")
+
+	manifest, err := m.loadManifest(manifestPath)
+	if err != nil {
+		return fmt.Errorf("reload manifest: %w", err)
+	}
+
+	wasEnabled := loaded.Info.State == plugin.StateEnabled
+	if loaded.Instance.FunctionExists("on_disable") {
+		_, _, _ = loaded.Instance.Call("on_disable", nil)
+	}
+	m.dispatcher.Unsubscribe(id)
+	loaded.Instance.Close()
+
+	wasmPath := filepath.Join(pluginPath, manifest.EntryPoint)
+	limits := m.cfg().GetEffectiveLimits(manifest.Limits)
+	extismManifest := extism.Manifest{
+		Wasm: []extism.Wasm{
+			extism.WasmFile{Path: wasmPath},
+		},
+		Memory: &extism.ManifestMemory{
+			MaxPages: uint32(limits.MaxMemoryMB * 16),
+		},
+	}
+
+	instance, err := extism.NewPlugin(m.ctx, extismManifest, extism.PluginConfig{EnableWasi: true}, m.createHostFunctions(loaded))
+	if err != nil {
+		loaded.Info.State = plugin.StateError
+		return fmt.Errorf("rebuild WASM instance: %w", err)
+	}
+
+	if _, _, err := instance.Call("plugin_init", nil); err != nil {
+		instance.Close()
+		loaded.Info.State = plugin.StateError
+		return fmt.Errorf("plugin_init after reload: %w", err)
+	}
+
+	loaded.Info.Manifest = manifest
+	loaded.Info.WASMPath = wasmPath
+	loaded.Instance = instance
+	loaded.Info.State = plugin.StateLoaded
+	loaded.Info.Metrics.RecordReload()
+
+	m.registerEventHandlers(loaded)
+
+	if wasEnabled {
+		loaded.Info.State = plugin.StateEnabling
+		if loaded.Instance.FunctionExists("on_enable") {
+			if _, _, err := loaded.Instance.Call("on_enable", nil); err != nil {
+				loaded.Info.State = plugin.StateError
+				return fmt.Errorf("on_enable after reload: %w", err)
+			}
+		}
+		loaded.Info.State = plugin.StateEnabled
+		loaded.Info.EnabledAt = time.Now()
+	}
+
+	m.logger.Info("plugin hot-reloaded", zap.String("id", id))
+	return nil
+}
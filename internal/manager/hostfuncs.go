@@ -5,8 +5,16 @@ import (
 	"encoding/json"
 
 	extism "github.com/extism/go-sdk"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/host"
 )
 
+// The request/response shapes below reuse host.PlayerInfo, host.BlockInfo,
+// host.TeleportRequest, host.GetBlockRequest and host.SetBlockRequest so a
+// guest built against pkg/host's FunctionProvider and a guest loaded by this
+// manager see the same field names on the wire (both used to define their
+// own divergent structs, e.g. "player_uuid" here vs "uuid" there, for the
+// same call).
 type logRequest struct {
 	Level   string `json:"level"`
 	Message string `json:"message"`
@@ -17,94 +25,113 @@ type broadcastRequest struct {
 }
 
 type sendMessageRequest struct {
-	PlayerUUID string `json:"player_uuid"`
-	Message    string `json:"message"`
+	UUID    string `json:"uuid"`
+	Message string `json:"message"`
 }
 
 type getPlayerRequest struct {
-	PlayerUUID string `json:"player_uuid"`
-}
-
-type playerResponse struct {
-	UUID      string   `json:"uuid"`
-	Name      string   `json:"name"`
-	WorldName string   `json:"world_name"`
-	Position  position `json:"position"`
-	Error     string   `json:"error,omitempty"`
-}
-
-type position struct {
-	X float64 `json:"x"`
-	Y float64 `json:"y"`
-	Z float64 `json:"z"`
-}
-
-type blockPos struct {
-	X int `json:"x"`
-	Y int `json:"y"`
-	Z int `json:"z"`
+	UUID string `json:"uuid"`
 }
 
 type playersResponse struct {
-	Players []playerResponse `json:"players"`
-}
-
-type teleportRequest struct {
-	PlayerUUID string   `json:"player_uuid"`
-	Position   position `json:"position"`
-	WorldName  string   `json:"world_name"`
+	Players []host.PlayerInfo `json:"players"`
 }
 
 type kickRequest struct {
-	PlayerUUID string `json:"player_uuid"`
-	Reason     string `json:"reason"`
+	UUID   string `json:"uuid"`
+	Reason string `json:"reason"`
 }
 
 type setHealthRequest struct {
-	PlayerUUID string  `json:"player_uuid"`
-	Health     float32 `json:"health"`
+	UUID   string  `json:"uuid"`
+	Health float32 `json:"health"`
 }
 
 type setGamemodeRequest struct {
-	PlayerUUID string `json:"player_uuid"`
-	Gamemode   int32  `json:"gamemode"`
+	UUID     string `json:"uuid"`
+	GameMode int32  `json:"game_mode"`
 }
 
-type getBlockRequest struct {
-	WorldName string   `json:"world_name"`
-	Position  blockPos `json:"position"`
+type storageGetRequest struct {
+	Key string `json:"key"`
 }
 
-type blockResponse struct {
-	BlockType  string            `json:"block_type"`
-	Position   blockPos          `json:"position"`
-	Properties map[string]string `json:"properties"`
-	Error      string            `json:"error,omitempty"`
+type storageSetRequest struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
 }
 
-type setBlockRequest struct {
-	WorldName  string            `json:"world_name"`
-	Position   blockPos          `json:"position"`
-	BlockType  string            `json:"block_type"`
-	Properties map[string]string `json:"properties"`
+type storageDeleteRequest struct {
+	Key string `json:"key"`
 }
 
-func (m *Manager) createHostFunctions() []extism.HostFunction {
+// abiInterrupted is the stack[0] sentinel every host_* function below
+// returns when loaded's deadline fires mid-call. It's deliberately distinct
+// from the plain 0 these functions already use for "failed"/"not found", so
+// a guest that cares can tell "the host function failed" from "the host
+// function was interrupted" rather than timing out on its own.
+const abiInterrupted = ^uint64(0)
+
+// createHostFunctions builds the host_* functions for a single plugin
+// instance. Each one is created fresh per LoadedPlugin (instead of being
+// shared across every plugin) so it can close over that plugin's Deadline
+// and race its own work against resetDeadline, rather than only the
+// top-level handle_event select in createEventHandler doing so.
+func (m *Manager) createHostFunctions(loaded *LoadedPlugin) []extism.HostFunction {
 	return []extism.HostFunction{
+		m.hostABIVersion(),
 		m.hostLog(),
-		m.hostBroadcast(),
-		m.hostSendMessage(),
-		m.hostGetPlayer(),
-		m.hostGetOnlinePlayers(),
-		m.hostTeleportPlayer(),
-		m.hostKickPlayer(),
-		m.hostSetPlayerHealth(),
-		m.hostSetPlayerGamemode(),
-		m.hostGetBlock(),
-		m.hostSetBlock(),
+		m.hostBroadcast(loaded),
+		m.hostSendMessage(loaded),
+		m.hostGetPlayer(loaded),
+		m.hostGetOnlinePlayers(loaded),
+		m.hostTeleportPlayer(loaded),
+		m.hostKickPlayer(loaded),
+		m.hostSetPlayerHealth(loaded),
+		m.hostSetPlayerGamemode(loaded),
+		m.hostGetBlock(loaded),
+		m.hostSetBlock(loaded),
+		m.hostStorageGet(loaded),
+		m.hostStorageSet(loaded),
+		m.hostStorageDelete(loaded),
 	}
 }
 
+// runInterruptible runs work - a host function's body, ending in whatever
+// stack[0] value it computes - on its own goroutine and races it against
+// loaded's current deadline channel, read once up front the same way
+// createEventHandler reads it before racing handle_event. If the deadline
+// fires first, it returns abiInterrupted immediately instead of waiting for
+// a stuck serverAPI call (e.g. a slow get_block lookup) to finish, so the
+// plugin traps out of its host call and handle_event returns promptly
+// instead of leaving work's goroutine to leak.
+func runInterruptible(loaded *LoadedPlugin, work func() uint64) uint64 {
+	cancelCh := loaded.Deadline.channel()
+	resultCh := make(chan uint64, 1)
+	go func() { resultCh <- work() }()
+
+	select {
+	case <-cancelCh:
+		return abiInterrupted
+	case result := <-resultCh:
+		return result
+	}
+}
+
+// hostABIVersion reports host.ABIVersion so a guest can check it against the
+// ABI version it was built against before calling anything else, the same
+// negotiation host.FunctionProvider offers.
+func (m *Manager) hostABIVersion() extism.HostFunction {
+	return extism.NewHostFunctionWithStack(
+		"host_abi_version",
+		func(_ context.Context, _ *extism.CurrentPlugin, stack []uint64) {
+			stack[0] = uint64(host.ABIVersion)
+		},
+		[]extism.ValueType{},
+		[]extism.ValueType{extism.ValueTypeI64},
+	)
+}
+
 func (m *Manager) hostLog() extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_log",
@@ -135,7 +162,7 @@ func (m *Manager) hostLog() extism.HostFunction {
 	)
 }
 
-func (m *Manager) hostBroadcast() extism.HostFunction {
+func (m *Manager) hostBroadcast(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_broadcast",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
@@ -151,17 +178,20 @@ func (m *Manager) hostBroadcast() extism.HostFunction {
 				return
 			}
 
-			if m.serverAPI != nil {
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return 0
+				}
 				m.serverAPI.BroadcastMessage(req.Message)
-			}
-			stack[0] = 1
+				return 1
+			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
-func (m *Manager) hostSendMessage() extism.HostFunction {
+func (m *Manager) hostSendMessage(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_send_message",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
@@ -177,26 +207,24 @@ func (m *Manager) hostSendMessage() extism.HostFunction {
 				return
 			}
 
-			if m.serverAPI == nil {
-				stack[0] = 0
-				return
-			}
-
-			player, ok := m.serverAPI.GetPlayer(req.PlayerUUID)
-			if !ok {
-				stack[0] = 0
-				return
-			}
-
-			player.SendMessage(req.Message)
-			stack[0] = 1
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return 0
+				}
+				player, ok := m.serverAPI.GetPlayer(req.UUID)
+				if !ok {
+					return 0
+				}
+				player.SendMessage(req.Message)
+				return 1
+			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
-func (m *Manager) hostGetPlayer() extism.HostFunction {
+func (m *Manager) hostGetPlayer(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_get_player",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
@@ -212,28 +240,17 @@ func (m *Manager) hostGetPlayer() extism.HostFunction {
 				return
 			}
 
-			if m.serverAPI == nil {
-				stack[0] = writeError(p, "server API not available")
-				return
-			}
-
-			player, ok := m.serverAPI.GetPlayer(req.PlayerUUID)
-			if !ok {
-				stack[0] = writeError(p, "player not found")
-				return
-			}
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return writeError(p, "server API not available")
+				}
 
-			x, y, z := player.Position()
-			var worldName string
-			if w := player.World(); w != nil {
-				worldName = w.Name()
-			}
+				player, ok := m.serverAPI.GetPlayer(req.UUID)
+				if !ok {
+					return writeError(p, "player not found")
+				}
 
-			stack[0] = writeJSON(p, playerResponse{
-				UUID:      player.UUID(),
-				Name:      player.Name(),
-				WorldName: worldName,
-				Position:  position{X: x, Y: y, Z: z},
+				return writeJSON(p, playerInfoOf(player))
 			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
@@ -241,40 +258,30 @@ func (m *Manager) hostGetPlayer() extism.HostFunction {
 	)
 }
 
-func (m *Manager) hostGetOnlinePlayers() extism.HostFunction {
+func (m *Manager) hostGetOnlinePlayers(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_get_online_players",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
-			if m.serverAPI == nil {
-				stack[0] = writeError(p, "server API not available")
-				return
-			}
-
-			players := m.serverAPI.GetAllPlayers()
-			resp := playersResponse{Players: make([]playerResponse, 0, len(players))}
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return writeError(p, "server API not available")
+				}
 
-			for _, pl := range players {
-				x, y, z := pl.Position()
-				var worldName string
-				if w := pl.World(); w != nil {
-					worldName = w.Name()
+				players := m.serverAPI.GetAllPlayers()
+				resp := playersResponse{Players: make([]host.PlayerInfo, 0, len(players))}
+				for _, pl := range players {
+					resp.Players = append(resp.Players, playerInfoOf(pl))
 				}
-				resp.Players = append(resp.Players, playerResponse{
-					UUID:      pl.UUID(),
-					Name:      pl.Name(),
-					WorldName: worldName,
-					Position:  position{X: x, Y: y, Z: z},
-				})
-			}
 
-			stack[0] = writeJSON(p, resp)
+				return writeJSON(p, resp)
+			})
 		},
 		[]extism.ValueType{},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
-func (m *Manager) hostTeleportPlayer() extism.HostFunction {
+func (m *Manager) hostTeleportPlayer(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_teleport_player",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
@@ -284,35 +291,34 @@ func (m *Manager) hostTeleportPlayer() extism.HostFunction {
 				return
 			}
 
-			var req teleportRequest
+			var req host.TeleportRequest
 			if err := json.Unmarshal(data, &req); err != nil {
 				stack[0] = 0
 				return
 			}
 
-			if m.serverAPI == nil {
-				stack[0] = 0
-				return
-			}
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return 0
+				}
 
-			player, ok := m.serverAPI.GetPlayer(req.PlayerUUID)
-			if !ok {
-				stack[0] = 0
-				return
-			}
+				player, ok := m.serverAPI.GetPlayer(req.UUID)
+				if !ok {
+					return 0
+				}
 
-			if err := player.Teleport(req.Position.X, req.Position.Y, req.Position.Z, req.WorldName); err != nil {
-				stack[0] = 0
-				return
-			}
-			stack[0] = 1
+				if err := player.Teleport(req.X, req.Y, req.Z, req.World); err != nil {
+					return 0
+				}
+				return 1
+			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
-func (m *Manager) hostKickPlayer() extism.HostFunction {
+func (m *Manager) hostKickPlayer(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_kick_player",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
@@ -328,26 +334,26 @@ func (m *Manager) hostKickPlayer() extism.HostFunction {
 				return
 			}
 
-			if m.serverAPI == nil {
-				stack[0] = 0
-				return
-			}
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return 0
+				}
 
-			player, ok := m.serverAPI.GetPlayer(req.PlayerUUID)
-			if !ok {
-				stack[0] = 0
-				return
-			}
+				player, ok := m.serverAPI.GetPlayer(req.UUID)
+				if !ok {
+					return 0
+				}
 
-			player.Kick(req.Reason)
-			stack[0] = 1
+				player.Kick(req.Reason)
+				return 1
+			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
-func (m *Manager) hostSetPlayerHealth() extism.HostFunction {
+func (m *Manager) hostSetPlayerHealth(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		"host_set_player_health",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
@@ -363,63 +369,145 @@ func (m *Manager) hostSetPlayerHealth() extism.HostFunction {
 				return
 			}
 
-			if m.serverAPI == nil {
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return 0
+				}
+
+				player, ok := m.serverAPI.GetPlayer(req.UUID)
+				if !ok {
+					return 0
+				}
+
+				player.SetHealth(float64(req.Health))
+				return 1
+			})
+		},
+		[]extism.ValueType{extism.ValueTypeI64},
+		[]extism.ValueType{extism.ValueTypeI64},
+	)
+}
+
+func (m *Manager) hostSetPlayerGamemode(loaded *LoadedPlugin) extism.HostFunction {
+	return extism.NewHostFunctionWithStack(
+		"host_set_player_gamemode",
+		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
+			data, err := p.ReadBytes(stack[0])
+			if err != nil {
 				stack[0] = 0
 				return
 			}
 
-			player, ok := m.serverAPI.GetPlayer(req.PlayerUUID)
-			if !ok {
+			var req setGamemodeRequest
+			if err := json.Unmarshal(data, &req); err != nil {
 				stack[0] = 0
 				return
 			}
 
-			player.SetHealth(float64(req.Health))
-			stack[0] = 1
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return 0
+				}
+
+				player, ok := m.serverAPI.GetPlayer(req.UUID)
+				if !ok {
+					return 0
+				}
+
+				player.SetGameMode(int(req.GameMode))
+				return 1
+			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
-func (m *Manager) hostSetPlayerGamemode() extism.HostFunction {
+func (m *Manager) hostGetBlock(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
-		"host_set_player_gamemode",
+		"host_get_block",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
 			data, err := p.ReadBytes(stack[0])
 			if err != nil {
-				stack[0] = 0
+				stack[0] = writeError(p, "failed to read input")
 				return
 			}
 
-			var req setGamemodeRequest
+			var req host.GetBlockRequest
 			if err := json.Unmarshal(data, &req); err != nil {
-				stack[0] = 0
+				stack[0] = writeError(p, "failed to parse request")
 				return
 			}
 
-			if m.serverAPI == nil {
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return writeError(p, "server API not available")
+				}
+
+				world, ok := m.serverAPI.GetWorld(req.World)
+				if !ok {
+					world = m.serverAPI.GetDefaultWorld()
+				}
+
+				blockType, properties := world.GetBlock(int(req.X), int(req.Y), int(req.Z))
+
+				return writeJSON(p, host.BlockInfo{
+					BlockType:  blockType,
+					X:          req.X,
+					Y:          req.Y,
+					Z:          req.Z,
+					Properties: properties,
+				})
+			})
+		},
+		[]extism.ValueType{extism.ValueTypeI64},
+		[]extism.ValueType{extism.ValueTypeI64},
+	)
+}
+
+func (m *Manager) hostSetBlock(loaded *LoadedPlugin) extism.HostFunction {
+	return extism.NewHostFunctionWithStack(
+		"host_set_block",
+		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
+			data, err := p.ReadBytes(stack[0])
+			if err != nil {
 				stack[0] = 0
 				return
 			}
 
-			player, ok := m.serverAPI.GetPlayer(req.PlayerUUID)
-			if !ok {
+			var req host.SetBlockRequest
+			if err := json.Unmarshal(data, &req); err != nil {
 				stack[0] = 0
 				return
 			}
 
-			player.SetGameMode(int(req.Gamemode))
-			stack[0] = 1
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.serverAPI == nil {
+					return 0
+				}
+
+				world, ok := m.serverAPI.GetWorld(req.World)
+				if !ok {
+					world = m.serverAPI.GetDefaultWorld()
+				}
+
+				if err := world.SetBlock(int(req.X), int(req.Y), int(req.Z), req.BlockType, req.Properties); err != nil {
+					return 0
+				}
+				return 1
+			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
-func (m *Manager) hostGetBlock() extism.HostFunction {
+// hostStorageGet reads a key from loaded's own namespace in m.storage. It
+// returns stack[0] == 0 when the key isn't set; when storage isn't available
+// or the read fails it returns a writeError envelope instead.
+func (m *Manager) hostStorageGet(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
-		"host_get_block",
+		"host_storage_get",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
 			data, err := p.ReadBytes(stack[0])
 			if err != nil {
@@ -427,28 +515,29 @@ func (m *Manager) hostGetBlock() extism.HostFunction {
 				return
 			}
 
-			var req getBlockRequest
+			var req storageGetRequest
 			if err := json.Unmarshal(data, &req); err != nil {
 				stack[0] = writeError(p, "failed to parse request")
 				return
 			}
 
-			if m.serverAPI == nil {
-				stack[0] = writeError(p, "server API not available")
-				return
-			}
-
-			world, ok := m.serverAPI.GetWorld(req.WorldName)
-			if !ok {
-				world = m.serverAPI.GetDefaultWorld()
-			}
-
-			blockType, properties := world.GetBlock(req.Position.X, req.Position.Y, req.Position.Z)
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.storage == nil {
+					return writeError(p, "storage not available")
+				}
 
-			stack[0] = writeJSON(p, blockResponse{
-				BlockType:  blockType,
-				Position:   req.Position,
-				Properties: properties,
+				value, ok, err := m.storage.Get(loaded.Info.Manifest.ID, req.Key)
+				if err != nil {
+					return writeError(p, err.Error())
+				}
+				if !ok {
+					return 0
+				}
+				offset, err := p.WriteBytes(value)
+				if err != nil {
+					return 0
+				}
+				return offset
 			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
@@ -456,9 +545,9 @@ func (m *Manager) hostGetBlock() extism.HostFunction {
 	)
 }
 
-func (m *Manager) hostSetBlock() extism.HostFunction {
+func (m *Manager) hostStorageSet(loaded *LoadedPlugin) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
-		"host_set_block",
+		"host_storage_set",
 		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
 			data, err := p.ReadBytes(stack[0])
 			if err != nil {
@@ -466,33 +555,76 @@ func (m *Manager) hostSetBlock() extism.HostFunction {
 				return
 			}
 
-			var req setBlockRequest
+			var req storageSetRequest
 			if err := json.Unmarshal(data, &req); err != nil {
 				stack[0] = 0
 				return
 			}
 
-			if m.serverAPI == nil {
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.storage == nil {
+					return 0
+				}
+				if err := m.storage.Set(loaded.Info.Manifest.ID, req.Key, req.Value); err != nil {
+					return 0
+				}
+				return 1
+			})
+		},
+		[]extism.ValueType{extism.ValueTypeI64},
+		[]extism.ValueType{extism.ValueTypeI64},
+	)
+}
+
+func (m *Manager) hostStorageDelete(loaded *LoadedPlugin) extism.HostFunction {
+	return extism.NewHostFunctionWithStack(
+		"host_storage_delete",
+		func(_ context.Context, p *extism.CurrentPlugin, stack []uint64) {
+			data, err := p.ReadBytes(stack[0])
+			if err != nil {
 				stack[0] = 0
 				return
 			}
 
-			world, ok := m.serverAPI.GetWorld(req.WorldName)
-			if !ok {
-				world = m.serverAPI.GetDefaultWorld()
-			}
-
-			if err := world.SetBlock(req.Position.X, req.Position.Y, req.Position.Z, req.BlockType, req.Properties); err != nil {
+			var req storageDeleteRequest
+			if err := json.Unmarshal(data, &req); err != nil {
 				stack[0] = 0
 				return
 			}
-			stack[0] = 1
+
+			stack[0] = runInterruptible(loaded, func() uint64 {
+				if m.storage == nil {
+					return 0
+				}
+				if err := m.storage.Delete(loaded.Info.Manifest.ID, req.Key); err != nil {
+					return 0
+				}
+				return 1
+			})
 		},
 		[]extism.ValueType{extism.ValueTypeI64},
 		[]extism.ValueType{extism.ValueTypeI64},
 	)
 }
 
+// playerInfoOf snapshots a PlayerAPI into the wire-format host.PlayerInfo
+// shared with pkg/host's FunctionProvider.
+func playerInfoOf(p PlayerAPI) host.PlayerInfo {
+	x, y, z := p.Position()
+	var worldName string
+	if w := p.World(); w != nil {
+		worldName = w.Name()
+	}
+	return host.PlayerInfo{
+		UUID:      p.UUID(),
+		Name:      p.Name(),
+		X:         x,
+		Y:         y,
+		Z:         z,
+		WorldName: worldName,
+	}
+}
+
 func writeJSON(p *extism.CurrentPlugin, v any) uint64 {
 	data, err := json.Marshal(v)
 	if err != nil {
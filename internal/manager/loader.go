@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/EinBexiii/dragonfly-wasm/pkg/config"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/store"
 )
 
 type LoadError struct {
@@ -28,12 +31,27 @@ func (e *LoadError) Unwrap() error { return e.Err }
 type Loader struct {
 	config *config.Config
 	logger *zap.Logger
+
+	// store and ociClient are only set by NewLoaderWithStore. A Loader built
+	// via NewLoader can still Discover plugins already on disk but can't
+	// resolve a ref - InstallRef/UpgradeRef/RollbackRef all return an error
+	// saying so, rather than the caller needing a nil check of its own.
+	store     *store.Store
+	ociClient *store.Client
 }
 
 func NewLoader(cfg *config.Config, logger *zap.Logger) *Loader {
 	return &Loader{config: cfg, logger: logger}
 }
 
+// NewLoaderWithStore is like NewLoader but also wires up a content-
+// addressable Store and OCI Client, enabling InstallRef/UpgradeRef/
+// RollbackRef to resolve plugins by reference instead of only discovering
+// ones already unpacked under Config.PluginDir.
+func NewLoaderWithStore(cfg *config.Config, logger *zap.Logger, s *store.Store, client *store.Client) *Loader {
+	return &Loader{config: cfg, logger: logger, store: s, ociClient: client}
+}
+
 type DiscoveredPlugin struct {
 	Manifest  *plugin.Manifest
 	WASMPath  string
@@ -186,3 +204,141 @@ func (l *Loader) ResolveDependencies(plugins []DiscoveredPlugin) ([]DiscoveredPl
 
 	return sorted, nil
 }
+
+// errNoStore is returned by InstallRef/UpgradeRef/RollbackRef when this
+// Loader was built with NewLoader rather than NewLoaderWithStore.
+var errNoStore = errors.New("loader has no content-addressable store configured")
+
+// InstallRef pulls ref (a "repo/name:tag" or "repo/name@sha256:..."
+// reference) via the OCI client into the store, then materializes it under
+// Config.PluginDir/<alias> (or <ref.Repository>'s base name if alias is
+// empty) the same way a directory the operator unpacked there by hand would
+// look: plugin.toml plus the named EntryPoint file. Installing the same ref
+// again over an existing alias is how an in-place upgrade is done - the new
+// digest becomes current, and the old one survives in the store's history
+// for RollbackRef.
+func (l *Loader) InstallRef(ctx context.Context, rawRef, alias string) (*DiscoveredPlugin, error) {
+	if l.store == nil || l.ociClient == nil {
+		return nil, errNoStore
+	}
+
+	ref, err := store.ParseReference(rawRef)
+	if err != nil {
+		return nil, fmt.Errorf("parse reference %q: %w", rawRef, err)
+	}
+	if ref.Registry == "" {
+		return nil, fmt.Errorf("reference %q has no registry and no default is configured", rawRef)
+	}
+
+	result, err := l.ociClient.Pull(ctx, l.store, ref, nil)
+	if err != nil {
+		return nil, fmt.Errorf("pull %s: %w", rawRef, err)
+	}
+
+	if alias == "" {
+		alias = filepath.Base(ref.Repository)
+	}
+	return l.materialize(alias, result.WASMDigest, result.ManifestTOMLDigest)
+}
+
+// UpgradeRef is InstallRef under another name: pulling the same ref again
+// naturally re-resolves its tag to whatever the registry currently serves,
+// so upgrading in place is just re-running Install against the existing
+// alias.
+func (l *Loader) UpgradeRef(ctx context.Context, rawRef, alias string) (*DiscoveredPlugin, error) {
+	return l.InstallRef(ctx, rawRef, alias)
+}
+
+// RollbackRef moves alias back to a digest recorded in the store's history
+// (see Store.History) and re-materializes it, without contacting the
+// registry - the blobs are already on disk from when that digest was
+// originally pulled.
+func (l *Loader) RollbackRef(alias string, target store.Digest) (*DiscoveredPlugin, error) {
+	if l.store == nil {
+		return nil, errNoStore
+	}
+	if err := l.store.Rollback(alias, target); err != nil {
+		return nil, fmt.Errorf("rollback %s to %s: %w", alias, target, err)
+	}
+
+	manifestData, err := l.store.GetBlob(target)
+	if err != nil {
+		return nil, fmt.Errorf("read rolled-back manifest: %w", err)
+	}
+	var ociMan struct {
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+		} `json:"layers"`
+	}
+	if err := json.Unmarshal(manifestData, &ociMan); err != nil {
+		return nil, fmt.Errorf("parse rolled-back manifest: %w", err)
+	}
+
+	var wasmDigest, manifestTOMLDigest store.Digest
+	for _, layer := range ociMan.Layers {
+		switch layer.MediaType {
+		case store.WASMLayerMediaType:
+			wasmDigest = store.Digest(layer.Digest)
+		case store.ManifestLayerMediaType:
+			manifestTOMLDigest = store.Digest(layer.Digest)
+		}
+	}
+	if wasmDigest == "" || manifestTOMLDigest == "" {
+		return nil, fmt.Errorf("rolled-back manifest %s is missing a required layer", target)
+	}
+
+	return l.materialize(alias, wasmDigest, manifestTOMLDigest)
+}
+
+// materialize reads the manifest and WASM blobs for digests wasmDigest and
+// manifestTOMLDigest back out of the store - re-verifying each one's
+// content against its digest, so a digest resolved from the store's own
+// index is trusted no more than one read straight off a registry - and
+// writes them into Config.PluginDir/<alias>, mandatory verification meaning
+// a tampered blob fails here rather than silently loading.
+func (l *Loader) materialize(alias string, wasmDigest, manifestTOMLDigest store.Digest) (*DiscoveredPlugin, error) {
+	manifestTOML, err := l.store.GetBlob(manifestTOMLDigest)
+	if err != nil {
+		return nil, fmt.Errorf("verify manifest blob: %w", err)
+	}
+	wasmData, err := l.store.GetBlob(wasmDigest)
+	if err != nil {
+		return nil, fmt.Errorf("verify wasm blob: %w", err)
+	}
+
+	var manifest plugin.Manifest
+	if err := toml.Unmarshal(manifestTOML, &manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	dir := filepath.Join(l.config.PluginDir, alias)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create plugin directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plugin.toml"), manifestTOML, 0o644); err != nil {
+		return nil, fmt.Errorf("write manifest: %w", err)
+	}
+	wasmPath := filepath.Join(dir, manifest.EntryPoint)
+	if err := os.WriteFile(wasmPath, wasmData, 0o644); err != nil {
+		return nil, fmt.Errorf("write wasm: %w", err)
+	}
+
+	dataPath := filepath.Join(l.config.DataDir, manifest.ID)
+	if err := os.MkdirAll(dataPath, 0o755); err != nil {
+		return nil, fmt.Errorf("create data directory: %w", err)
+	}
+
+	l.logger.Info("installed plugin from store",
+		zap.String("alias", alias), zap.String("id", manifest.ID), zap.String("version", manifest.Version.String()))
+
+	return &DiscoveredPlugin{
+		Manifest:  &manifest,
+		WASMPath:  wasmPath,
+		DataPath:  dataPath,
+		Directory: dir,
+	}, nil
+}
@@ -9,34 +9,73 @@ import (
 	"slices"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	extism "github.com/extism/go-sdk"
 	"github.com/pelletier/go-toml/v2"
 	"go.uber.org/zap"
 
+	"github.com/EinBexiii/dragonfly-wasm/pkg/chat"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/config"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/events"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events/proto"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/pluginregistry"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/store"
 )
 
+// PluginCommander is what "plugins list" / "plugins update" CLI verbs need
+// from Manager, declared here against Manager's own exposed methods the
+// same way ServerAPI/PlayerAPI/WorldAPI are declared against the host
+// embedder's capabilities rather than a concrete implementation type.
+type PluginCommander interface {
+	ListPluginSources() []pluginregistry.PluginSource
+	SyncPlugins(ctx context.Context) error
+}
+
+var _ PluginCommander = (*Manager)(nil)
+
 type Manager struct {
-	config     *config.Config
+	config     atomic.Pointer[config.Config]
 	logger     *zap.Logger
 	dispatcher *events.Dispatcher
+	lifecycle  *events.LifecycleBus
 	plugins    map[string]*LoadedPlugin
 	loadOrder  []string
 	mu         sync.RWMutex
-	hostFuncs  []extism.HostFunction
 	serverAPI  ServerAPI
 	ctx        context.Context
 	cancel     context.CancelFunc
+
+	devWatchersMu sync.Mutex
+	devWatchers   map[string]context.CancelFunc
+
+	pluginRegistry *pluginregistry.Manager
+
+	// loader resolves OCI-ref plugin installs onto the content-addressable
+	// store; nil unless Config.OCI.Enabled, in which case
+	// InstallPluginRef/UpgradePluginRef/RollbackPluginRef report that
+	// explicitly rather than needing a nil check of their own.
+	loader *Loader
+
+	// storage backs host_storage_get/set/delete. nil if NewStorage failed to
+	// open cfg.StorageBackend, in which case those host functions report
+	// "storage not available".
+	storage Storage
 }
 
 type LoadedPlugin struct {
 	Info     *plugin.Info
 	Instance *extism.Plugin
 	mu       sync.Mutex
+
+	// Deadline is shared by every host_* call this plugin's current
+	// instance makes. createEventHandler arms it for the duration of each
+	// handle_event dispatch so a host function blocked on, say, a slow
+	// get_block lookup unblocks as soon as the handler times out instead of
+	// outliving it.
+	Deadline *deadline
 }
 
 type ServerAPI interface {
@@ -45,12 +84,14 @@ type ServerAPI interface {
 	GetWorld(name string) (WorldAPI, bool)
 	GetDefaultWorld() WorldAPI
 	BroadcastMessage(msg string)
+	BroadcastComponent(c chat.Component)
 }
 
 type PlayerAPI interface {
 	UUID() string
 	Name() string
 	SendMessage(msg string)
+	SendComponent(c chat.Component)
 	Teleport(x, y, z float64, worldName string) error
 	Kick(reason string)
 	SetHealth(health float64)
@@ -68,24 +109,118 @@ type WorldAPI interface {
 func New(cfg *config.Config, logger *zap.Logger, serverAPI ServerAPI) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &Manager{
-		config:     cfg,
 		logger:     logger.Named("plugin-manager"),
-		dispatcher: events.NewDispatcher(logger.Named("event-dispatcher")),
+		dispatcher: events.NewDispatcherWithConfig(logger.Named("event-dispatcher"), workerPoolConfig(cfg)),
+		lifecycle:  events.NewLifecycleBus(),
 		plugins:    make(map[string]*LoadedPlugin),
 		serverAPI:  serverAPI,
 		ctx:        ctx,
 		cancel:     cancel,
 	}
-	m.hostFuncs = m.createHostFunctions()
+	m.config.Store(cfg)
+	m.pluginRegistry = pluginregistry.New(cfg.PluginDir, cfg.Performance.CacheDir, cfg.Plugins, cfg.Security.RequireSignedPlugins, cfg.Security.TrustedPublicKeys, m.logger)
+
+	storagePath := filepath.Join(cfg.DataDir, "_storage")
+	if s, err := NewStorage(cfg.StorageBackend, storagePath); err != nil {
+		m.logger.Warn("failed to open plugin storage backend, host_storage_* disabled", zap.Error(err))
+	} else {
+		m.storage = m.decorateStorage(s, cfg)
+	}
+
+	if cfg.OCI.Enabled {
+		s, err := store.NewStore(cfg.OCI.StoreDir)
+		if err != nil {
+			m.logger.Warn("failed to open oci plugin store, oci installs disabled", zap.Error(err))
+		} else {
+			m.loader = NewLoaderWithStore(cfg, m.logger, s, store.NewClient(nil, nil))
+		}
+	}
+
 	return m
 }
 
+// decorateStorage wraps inner with EncryptedStorage, if cfg.Security
+// sets a StorageEncryptionKeyFile, and then unconditionally with
+// QuotaStorage, so host_storage_set enforces a plugin's MaxStorageBytes
+// the moment Config.GetEffectiveLimits resolves a nonzero one rather than
+// needing a config change to turn enforcement on. The key file is read
+// once here so a missing or unreadable key fails loud at startup instead
+// of on the first host_storage_set call.
+func (m *Manager) decorateStorage(inner Storage, cfg *config.Config) Storage {
+	storage := inner
+	if cfg.Security.StorageEncryptionKeyFile != "" {
+		key, err := os.ReadFile(cfg.Security.StorageEncryptionKeyFile)
+		if err != nil {
+			m.logger.Warn("failed to read storage encryption key, plugin storage will be unencrypted", zap.Error(err))
+		} else {
+			storage = NewEncryptedStorage(storage, key)
+		}
+	}
+
+	usagePath := filepath.Join(cfg.DataDir, "_storage", "quota.json")
+	quota, err := NewQuotaStorage(storage, cfg, m.storageLimitsFor, usagePath)
+	if err != nil {
+		m.logger.Warn("failed to load storage quota snapshot, quotas will recount from zero", zap.Error(err))
+		quota, _ = NewQuotaStorage(storage, cfg, m.storageLimitsFor, "")
+		quota.usagePath = usagePath
+	}
+	return quota
+}
+
+// storageLimitsFor is the LimitsLookup QuotaStorage uses to resolve a
+// plugin's declared plugin.ResourceLimits, the same limits
+// createEventHandler's deadline and loadPlugin's memory cap start from.
+func (m *Manager) storageLimitsFor(pluginID string) (plugin.ResourceLimits, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	loaded, ok := m.plugins[pluginID]
+	if !ok {
+		return plugin.ResourceLimits{}, false
+	}
+	return loaded.Info.Manifest.Limits, true
+}
+
+// cfg returns the active Config. It's a thin wrapper over the
+// atomic.Pointer so every read sees either the config Manager was built
+// with or, after ApplyConfig, the latest one that parsed and validated -
+// never a partially-applied one.
+func (m *Manager) cfg() *config.Config {
+	return m.config.Load()
+}
+
+// ListPluginSources reports the plugin sources configured under
+// Config.Plugins, satisfying PluginCommander.
+func (m *Manager) ListPluginSources() []pluginregistry.PluginSource {
+	return m.pluginRegistry.ListSources()
+}
+
+// SyncPlugins downloads and verifies every configured plugin source into
+// PluginDir, satisfying PluginCommander. It only installs files; call
+// LoadAll (or RefreshRegistry) afterward to pick up anything new.
+func (m *Manager) SyncPlugins(ctx context.Context) error {
+	return m.pluginRegistry.Sync(ctx)
+}
+
+// workerPoolConfig derives the dispatcher's per-plugin pool sizing from
+// cfg.Performance. A single worker is used unless ParallelEventDispatch is
+// set, since an extism plugin instance already serializes its own calls.
+func workerPoolConfig(cfg *config.Config) events.WorkerPoolConfig {
+	pc := events.DefaultWorkerPoolConfig()
+	if cfg.Performance.EventQueueSize > 0 {
+		pc.QueueSize = cfg.Performance.EventQueueSize
+	}
+	if cfg.Performance.ParallelEventDispatch && cfg.Performance.WorkerCount > 0 {
+		pc.Workers = cfg.Performance.WorkerCount
+	}
+	return pc
+}
+
 func (m *Manager) LoadAll(ctx context.Context) error {
-	entries, err := os.ReadDir(m.config.PluginDir)
+	entries, err := os.ReadDir(m.cfg().PluginDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			m.logger.Info("creating plugin directory", zap.String("path", m.config.PluginDir))
-			return os.MkdirAll(m.config.PluginDir, 0o755)
+			m.logger.Info("creating plugin directory", zap.String("path", m.cfg().PluginDir))
+			return os.MkdirAll(m.cfg().PluginDir, 0o755)
 		}
 		return fmt.Errorf("read plugin directory: %w", err)
 	}
@@ -98,7 +233,7 @@ func (m *Manager) LoadAll(ctx context.Context) error {
 			continue
 		}
 
-		pluginPath := filepath.Join(m.config.PluginDir, entry.Name())
+		pluginPath := filepath.Join(m.cfg().PluginDir, entry.Name())
 		manifestPath := filepath.Join(pluginPath, "plugin.toml")
 
 		manifest, err := m.loadManifest(manifestPath)
@@ -106,8 +241,13 @@ func (m *Manager) LoadAll(ctx context.Context) error {
 			m.logger.Warn("failed to load manifest", zap.String("path", manifestPath), zap.Error(err))
 			continue
 		}
+		m.lifecycle.Emit(events.LifecycleEvent{
+			Type:     events.LifecyclePluginDiscovered,
+			PluginID: plugin.PluginID(manifest.ID),
+			Time:     time.Now(),
+		})
 
-		if !m.config.IsPluginEnabled(manifest.ID) {
+		if !m.cfg().IsPluginEnabled(manifest.ID) {
 			m.logger.Debug("plugin disabled", zap.String("id", manifest.ID))
 			continue
 		}
@@ -116,6 +256,12 @@ func (m *Manager) LoadAll(ctx context.Context) error {
 		manifestPaths[manifest.ID] = pluginPath
 	}
 
+	remoteManifests, remotePaths := m.loadRemoteManifests(ctx)
+	manifests = append(manifests, remoteManifests...)
+	for id, path := range remotePaths {
+		manifestPaths[id] = path
+	}
+
 	sorted, err := m.sortByDependencies(manifests)
 	if err != nil {
 		return fmt.Errorf("dependency resolution: %w", err)
@@ -127,10 +273,47 @@ func (m *Manager) LoadAll(ctx context.Context) error {
 		}
 	}
 
+	m.restoreEnableState()
+
 	m.logger.Info("plugins loaded", zap.Int("count", len(m.plugins)))
 	return nil
 }
 
+// restoreEnableState consults the persisted state store after a topological
+// load and auto-enables, in load order, every plugin whose last recorded
+// state was StateEnabled. A plugin the operator explicitly left disabled
+// (or one never recorded) is left exactly where loadPlugin put it:
+// StateLoaded, regardless of what config.IsPluginEnabled would otherwise
+// allow.
+func (m *Manager) restoreEnableState() {
+	states, err := m.loadStateStore()
+	if err != nil {
+		m.logger.Warn("failed to read plugin state store", zap.Error(err))
+		return
+	}
+
+	m.mu.RLock()
+	ids := slices.Clone(m.loadOrder)
+	m.mu.RUnlock()
+
+	for _, id := range ids {
+		loaded, ok := m.GetPlugin(id)
+		if !ok {
+			continue
+		}
+
+		last, recorded := states[id]
+		loaded.Info.LastKnownState = last
+		if !recorded || last != plugin.StateEnabled {
+			continue
+		}
+
+		if err := m.EnablePlugin(id); err != nil {
+			m.logger.Error("failed to restore plugin enable state", zap.String("id", id), zap.Error(err))
+		}
+	}
+}
+
 func (m *Manager) loadManifest(path string) (*plugin.Manifest, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -163,15 +346,25 @@ func (m *Manager) sortByDependencies(manifests []*plugin.Manifest) ([]*plugin.Ma
 		}
 
 		for _, dep := range manifest.Dependencies {
-			if !dep.Optional {
-				if _, exists := manifestMap[dep.ID]; !exists {
+			found, exists := manifestMap[dep.ID]
+			if !exists {
+				if !dep.Optional {
 					return nil, fmt.Errorf("plugin %s requires missing dependency %s", manifest.ID, dep.ID)
 				}
+				continue
 			}
-			if _, exists := manifestMap[dep.ID]; exists {
-				inDegree[manifest.ID]++
-				dependents[dep.ID] = append(dependents[dep.ID], manifest.ID)
+
+			if !dep.Constraint.Matches(found.Version) {
+				return nil, &ErrDependencyNotMet{
+					PluginID:     manifest.ID,
+					DependencyID: dep.ID,
+					Constraint:   dep.Constraint,
+					Actual:       found.Version,
+				}
 			}
+
+			inDegree[manifest.ID]++
+			dependents[dep.ID] = append(dependents[dep.ID], manifest.ID)
 		}
 
 		for _, id := range manifest.LoadAfter {
@@ -180,6 +373,13 @@ func (m *Manager) sortByDependencies(manifests []*plugin.Manifest) ([]*plugin.Ma
 				dependents[id] = append(dependents[id], manifest.ID)
 			}
 		}
+
+		for _, id := range manifest.LoadBefore {
+			if _, exists := manifestMap[id]; exists {
+				inDegree[id]++
+				dependents[manifest.ID] = append(dependents[manifest.ID], id)
+			}
+		}
 	}
 
 	var queue []string
@@ -211,6 +411,56 @@ func (m *Manager) sortByDependencies(manifests []*plugin.Manifest) ([]*plugin.Ma
 	return result, nil
 }
 
+// ErrDependencyNotMet reports that PluginID declared a dependency on
+// DependencyID satisfying Constraint, but the dependency actually resolved
+// to Actual.
+type ErrDependencyNotMet struct {
+	PluginID     string
+	DependencyID string
+	Constraint   plugin.VersionConstraint
+	Actual       plugin.Version
+}
+
+func (e *ErrDependencyNotMet) Error() string {
+	return fmt.Sprintf("plugin %s requires %s %s, found %s", e.PluginID, e.DependencyID, e.Constraint, e.Actual)
+}
+
+// hostAPIVersionRange is the range of Manifest.APIVersion values this host
+// will load, derived from plugin.CurrentABIVersion the same way
+// plugin.ABICompatible already gates loading elsewhere: only the major
+// version needs to match.
+func hostAPIVersionRange() plugin.VersionConstraint {
+	c, err := plugin.ParseVersionConstraint(fmt.Sprintf(">=%d.0.0, <%d.0.0", plugin.CurrentABIVersion.Major, plugin.CurrentABIVersion.Major+1))
+	if err != nil {
+		panic(fmt.Sprintf("hostAPIVersionRange: built-in constraint failed to parse: %v", err))
+	}
+	return c
+}
+
+// ResolveDependencies validates manifests against each other — dependency
+// existence, version constraints, and APIVersion compatibility with this
+// host — then topologically sorts them the same way sortByDependencies
+// does for LoadAll, returning the IDs in safe load order.
+func (m *Manager) ResolveDependencies(manifests []*plugin.Manifest) ([]plugin.PluginID, error) {
+	apiRange := hostAPIVersionRange()
+	for _, manifest := range manifests {
+		if !apiRange.Matches(manifest.APIVersion) {
+			return nil, fmt.Errorf("plugin %s targets API version %s, host supports %s", manifest.ID, manifest.APIVersion, apiRange)
+		}
+	}
+
+	sorted, err := m.sortByDependencies(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]plugin.PluginID, 0, len(sorted))
+	for _, manifest := range sorted {
+		ids = append(ids, plugin.PluginID(manifest.ID))
+	}
+	return ids, nil
+}
+
 func (m *Manager) loadPlugin(ctx context.Context, manifest *plugin.Manifest, pluginPath string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -219,8 +469,12 @@ func (m *Manager) loadPlugin(ctx context.Context, manifest *plugin.Manifest, plu
 		return fmt.Errorf("plugin %s already loaded", manifest.ID)
 	}
 
+	if !plugin.ABICompatible(manifest.APIVersion) {
+		return fmt.Errorf("plugin %s targets ABI %s, host is on %s", manifest.ID, manifest.APIVersion, plugin.CurrentABIVersion)
+	}
+
 	wasmPath := filepath.Join(pluginPath, manifest.EntryPoint)
-	dataPath := filepath.Join(m.config.DataDir, manifest.ID)
+	dataPath := filepath.Join(m.cfg().DataDir, manifest.ID)
 
 	if err := os.MkdirAll(dataPath, 0o755); err != nil {
 		return fmt.Errorf("create data directory: %w", err)
@@ -230,7 +484,7 @@ func (m *Manager) loadPlugin(ctx context.Context, manifest *plugin.Manifest, plu
 	info.State = plugin.StateLoading
 	info.LoadedAt = time.Now()
 
-	limits := m.config.GetEffectiveLimits(manifest.Limits)
+	limits := m.cfg().GetEffectiveLimits(manifest.Limits)
 	extismManifest := extism.Manifest{
 		Wasm: []extism.Wasm{
 			extism.WasmFile{Path: wasmPath},
@@ -240,7 +494,9 @@ func (m *Manager) loadPlugin(ctx context.Context, manifest *plugin.Manifest, plu
 		},
 	}
 
-	instance, err := extism.NewPlugin(ctx, extismManifest, extism.PluginConfig{EnableWasi: true}, m.hostFuncs)
+	loaded := &LoadedPlugin{Info: info, Deadline: newDeadline()}
+
+	instance, err := extism.NewPlugin(ctx, extismManifest, extism.PluginConfig{EnableWasi: true}, m.createHostFunctions(loaded))
 	if err != nil {
 		info.State = plugin.StateError
 		return fmt.Errorf("create WASM instance: %w", err)
@@ -255,7 +511,7 @@ func (m *Manager) loadPlugin(ctx context.Context, manifest *plugin.Manifest, plu
 		return errors.New("missing required export: handle_event")
 	}
 
-	loaded := &LoadedPlugin{Info: info, Instance: instance}
+	loaded.Instance = instance
 
 	initCtx, cancel := context.WithTimeout(ctx, time.Duration(limits.MaxExecutionMs)*time.Millisecond*10)
 	defer cancel()
@@ -275,6 +531,13 @@ func (m *Manager) loadPlugin(ctx context.Context, manifest *plugin.Manifest, plu
 	m.registerEventHandlers(loaded)
 
 	m.logger.Info("plugin loaded", zap.String("id", manifest.ID), zap.String("version", manifest.Version.String()))
+	m.lifecycle.Emit(events.LifecycleEvent{
+		Type:     events.LifecyclePluginLoaded,
+		PluginID: plugin.PluginID(manifest.ID),
+		Previous: plugin.StateLoading,
+		Next:     plugin.StateLoaded,
+		Time:     time.Now(),
+	})
 	return nil
 }
 
@@ -298,10 +561,14 @@ func (m *Manager) createEventHandler(loaded *LoadedPlugin, eventType plugin.Even
 			return nil, nil
 		}
 
-		limits := m.config.GetEffectiveLimits(loaded.Info.Manifest.Limits)
-		ctx, cancel := context.WithTimeout(ctx, time.Duration(limits.MaxExecutionMs)*time.Millisecond)
+		limits := m.cfg().GetEffectiveLimits(loaded.Info.Manifest.Limits)
+		timeout := time.Duration(limits.MaxExecutionMs) * time.Millisecond
+		ctx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
+		loaded.Deadline.setDeadline(timeout)
+		defer loaded.Deadline.setDeadline(0)
+
 		start := time.Now()
 		envelope := append([]byte(eventType), 0)
 		envelope = append(envelope, data...)
@@ -312,7 +579,16 @@ func (m *Manager) createEventHandler(loaded *LoadedPlugin, eventType plugin.Even
 		}, 1)
 
 		go func() {
-			_, output, err := loaded.Instance.Call("handle_event", envelope)
+			var output []byte
+			var err error
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("handle_event panicked: %v", r)
+					}
+				}()
+				_, output, err = loaded.Instance.Call("handle_event", envelope)
+			}()
 			resultCh <- struct {
 				output []byte
 				err    error
@@ -322,24 +598,33 @@ func (m *Manager) createEventHandler(loaded *LoadedPlugin, eventType plugin.Even
 		select {
 		case <-ctx.Done():
 			loaded.Info.Metrics.RecordError(ctx.Err())
+			m.lifecycle.Emit(events.LifecycleEvent{
+				Type:     events.LifecyclePluginCallTimeout,
+				PluginID: plugin.PluginID(loaded.Info.Manifest.ID),
+				Time:     time.Now(),
+				Err:      ctx.Err(),
+			})
 			return nil, fmt.Errorf("handler timeout for %s", eventType)
 
 		case result := <-resultCh:
-			loaded.Info.Metrics.RecordCall(time.Since(start))
+			duration := time.Since(start)
+			loaded.Info.Metrics.RecordCall(duration)
 			if result.err != nil {
 				loaded.Info.Metrics.RecordError(result.err)
+				go m.superviseCrash(loaded, result.err)
 				return nil, result.err
 			}
 
 			eventResult := parseEventResult(result.output)
 			loaded.Info.Metrics.RecordEvent(eventType, eventResult.Cancelled)
+			loaded.Info.Metrics.RecordEventDuration(eventType, duration)
 			return eventResult, nil
 		}
 	}
 }
 
 func parseEventResult(data []byte) *events.EventResult {
-	result := &events.EventResult{Modifications: make(map[string]string)}
+	result := &events.EventResult{Patch: proto.NewPatch()}
 	if len(data) > 0 {
 		result.Cancelled = data[0] == 1
 	}
@@ -366,19 +651,36 @@ func (m *Manager) EnablePlugin(id string) error {
 		return fmt.Errorf("plugin %s cannot be enabled in state %s", id, loaded.Info.State)
 	}
 
+	previous := loaded.Info.State
 	loaded.Info.State = plugin.StateEnabling
+	m.lifecycle.Emit(events.LifecycleEvent{
+		Type: events.LifecyclePluginEnabling, PluginID: plugin.PluginID(id),
+		Previous: previous, Next: plugin.StateEnabling, Time: time.Now(),
+	})
 
 	if loaded.Instance.FunctionExists("on_enable") {
 		if _, _, err := loaded.Instance.Call("on_enable", nil); err != nil {
 			loaded.Info.State = plugin.StateError
 			loaded.Info.Metrics.RecordError(err)
+			m.lifecycle.Emit(events.LifecycleEvent{
+				Type: events.LifecyclePluginErrored, PluginID: plugin.PluginID(id),
+				Previous: plugin.StateEnabling, Next: plugin.StateError, Time: time.Now(), Err: err,
+			})
 			return fmt.Errorf("enable callback failed: %w", err)
 		}
 	}
 
 	loaded.Info.State = plugin.StateEnabled
+	loaded.Info.LastKnownState = plugin.StateEnabled
 	loaded.Info.EnabledAt = time.Now()
+	if err := m.persistState(id, plugin.StateEnabled); err != nil {
+		m.logger.Warn("failed to persist plugin state", zap.String("id", id), zap.Error(err))
+	}
 	m.logger.Info("plugin enabled", zap.String("id", id))
+	m.lifecycle.Emit(events.LifecycleEvent{
+		Type: events.LifecyclePluginEnabled, PluginID: plugin.PluginID(id),
+		Previous: plugin.StateEnabling, Next: plugin.StateEnabled, Time: time.Now(),
+	})
 	return nil
 }
 
@@ -399,14 +701,30 @@ func (m *Manager) DisablePlugin(id string) error {
 	}
 
 	loaded.Info.State = plugin.StateDisabling
+	m.lifecycle.Emit(events.LifecycleEvent{
+		Type: events.LifecyclePluginDisabling, PluginID: plugin.PluginID(id),
+		Previous: plugin.StateEnabled, Next: plugin.StateDisabling, Time: time.Now(),
+	})
 
 	if loaded.Instance.FunctionExists("on_disable") {
 		_, _, _ = loaded.Instance.Call("on_disable", nil)
 	}
 
 	loaded.Info.State = plugin.StateDisabled
+	loaded.Info.LastKnownState = plugin.StateDisabled
 	loaded.Info.DisabledAt = time.Now()
+	// Close cancels m.ctx before DisableAll, so disables during shutdown
+	// leave the state store recording what was enabled before it.
+	if m.ctx.Err() == nil {
+		if err := m.persistState(id, plugin.StateDisabled); err != nil {
+			m.logger.Warn("failed to persist plugin state", zap.String("id", id), zap.Error(err))
+		}
+	}
 	m.logger.Info("plugin disabled", zap.String("id", id))
+	m.lifecycle.Emit(events.LifecycleEvent{
+		Type: events.LifecyclePluginDisabled, PluginID: plugin.PluginID(id),
+		Previous: plugin.StateDisabling, Next: plugin.StateDisabled, Time: time.Now(),
+	})
 	return nil
 }
 
@@ -426,15 +744,24 @@ func (m *Manager) UnloadPlugin(id string) error {
 		m.mu.Lock()
 	}
 
-	m.dispatcher.Unsubscribe(id)
+	m.dispatcher.RemovePlugin(id)
 	loaded.Instance.Close()
+	previous := loaded.Info.State
 	loaded.Info.State = plugin.StateUnloaded
 
 	delete(m.plugins, id)
 	m.loadOrder = slices.DeleteFunc(m.loadOrder, func(s string) bool { return s == id })
 	m.mu.Unlock()
 
+	if err := m.ResetState(id); err != nil {
+		m.logger.Warn("failed to clear persisted plugin state", zap.String("id", id), zap.Error(err))
+	}
+
 	m.logger.Info("plugin unloaded", zap.String("id", id))
+	m.lifecycle.Emit(events.LifecycleEvent{
+		Type: events.LifecyclePluginUnloaded, PluginID: plugin.PluginID(id),
+		Previous: previous, Next: plugin.StateUnloaded, Time: time.Now(),
+	})
 	return nil
 }
 
@@ -468,6 +795,13 @@ func (m *Manager) Close() error {
 	m.cancel()
 	m.DisableAll()
 
+	m.devWatchersMu.Lock()
+	for _, cancel := range m.devWatchers {
+		cancel()
+	}
+	m.devWatchers = nil
+	m.devWatchersMu.Unlock()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -475,6 +809,12 @@ func (m *Manager) Close() error {
 		loaded.Instance.Close()
 		delete(m.plugins, id)
 	}
+
+	if m.storage != nil {
+		if err := m.storage.Close(); err != nil {
+			m.logger.Warn("failed to close plugin storage backend", zap.Error(err))
+		}
+	}
 	return nil
 }
 
@@ -499,3 +839,11 @@ func (m *Manager) GetAllPlugins() []*LoadedPlugin {
 func (m *Manager) Dispatcher() *events.Dispatcher {
 	return m.dispatcher
 }
+
+// Lifecycle returns the bus that broadcasts plugin state transitions -
+// discovery, load, enable/disable, unload, crashes, and call timeouts - to
+// anything that subscribes, independent of Dispatcher's in-game event
+// routing.
+func (m *Manager) Lifecycle() *events.LifecycleBus {
+	return m.lifecycle
+}
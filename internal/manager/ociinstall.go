@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/store"
+)
+
+// errOCIDisabled is returned by InstallPluginRef/UpgradePluginRef/
+// RollbackPluginRef when Manager was built without Config.OCI.Enabled.
+var errOCIDisabled = errors.New("oci plugin installs are disabled: set oci.enabled in Config")
+
+// InstallPluginRef pulls ref (a "repo/name:tag" or "repo/name@sha256:..."
+// reference) from the configured OCI registry, materializes it under
+// PluginDir/alias, and loads it immediately - equivalent to unpacking a
+// plugin under PluginDir by hand and calling LoadAll, but without a
+// restart. alias defaults to ref's repository name if empty.
+func (m *Manager) InstallPluginRef(ctx context.Context, ref, alias string) error {
+	if m.loader == nil {
+		return errOCIDisabled
+	}
+	dp, err := m.loader.InstallRef(ctx, ref, alias)
+	if err != nil {
+		return fmt.Errorf("install %s: %w", ref, err)
+	}
+	return m.loadPlugin(ctx, dp.Manifest, dp.Directory)
+}
+
+// UpgradePluginRef re-pulls ref and reloads alias with whatever the
+// registry currently serves, unloading the previously loaded version first
+// if alias is already running - the same unload-then-load sequence
+// RefreshRegistry uses for a changed remote-source entry.
+func (m *Manager) UpgradePluginRef(ctx context.Context, ref, alias string) error {
+	if m.loader == nil {
+		return errOCIDisabled
+	}
+	dp, err := m.loader.UpgradeRef(ctx, ref, alias)
+	if err != nil {
+		return fmt.Errorf("upgrade %s: %w", ref, err)
+	}
+	return m.reloadDiscovered(ctx, dp)
+}
+
+// RollbackPluginRef moves alias back to a digest recorded in the store's
+// history (see store.Store.History) and reloads it, without contacting the
+// registry.
+func (m *Manager) RollbackPluginRef(ctx context.Context, alias string, target store.Digest) error {
+	if m.loader == nil {
+		return errOCIDisabled
+	}
+	dp, err := m.loader.RollbackRef(alias, target)
+	if err != nil {
+		return fmt.Errorf("rollback %s: %w", alias, err)
+	}
+	return m.reloadDiscovered(ctx, dp)
+}
+
+// reloadDiscovered unloads dp.Manifest.ID's currently running instance, if
+// any, before loading the newly materialized one in its place.
+func (m *Manager) reloadDiscovered(ctx context.Context, dp *DiscoveredPlugin) error {
+	if _, exists := m.GetPlugin(dp.Manifest.ID); exists {
+		if err := m.UnloadPlugin(dp.Manifest.ID); err != nil {
+			return fmt.Errorf("unload previous version: %w", err)
+		}
+	}
+	return m.loadPlugin(ctx, dp.Manifest, dp.Directory)
+}
@@ -0,0 +1,224 @@
+package manager
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/config"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/verify"
+)
+
+// remoteCacheDir is the config.DataDir subdirectory loadRemoteManifests
+// downloads verified .wasm files into, at remoteCacheDir/<id>/<version>/.
+const remoteCacheDir = "_cache"
+
+const remoteWasmFile = "plugin.wasm"
+
+// registryEntry is one row of a RemoteSource's index.json. Sig is computed
+// per entry (over ID|Version|WasmURL|SHA256) rather than over the whole
+// index, so a source can mix plugins signed by different publishers
+// without re-signing every entry whenever one of them changes.
+type registryEntry struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	WasmURL string `json:"wasm_url"`
+	SHA256  string `json:"sha256"`
+	Sig     string `json:"sig"`
+}
+
+type registryIndex struct {
+	Plugins []registryEntry `json:"plugins"`
+}
+
+// signingMessage is the canonical byte string a registryEntry's Sig covers.
+func (e registryEntry) signingMessage() []byte {
+	return []byte(strings.Join([]string{e.ID, e.Version, e.WasmURL, e.SHA256}, "|"))
+}
+
+// loadRemoteManifests fetches and verifies the index for every configured
+// RemoteSource and returns a synthesized plugin.Manifest plus its cache
+// directory for each entry that verified and downloaded successfully. A
+// source whose index fails to fetch, parse or verify (one entry with a bad
+// signature rejects that source's whole index) is logged and skipped, and so
+// is an entry that fails to download, rather than failing the whole call,
+// matching loadManifest's treatment of a malformed local plugin.toml.
+func (m *Manager) loadRemoteManifests(ctx context.Context) ([]*plugin.Manifest, map[string]string) {
+	var manifests []*plugin.Manifest
+	paths := make(map[string]string)
+
+	for _, src := range m.cfg().RemoteSources {
+		entries, err := fetchRegistryIndex(ctx, src)
+		if err != nil {
+			m.logger.Error("failed to fetch remote registry index", zap.String("url", src.URL), zap.Error(err))
+			continue
+		}
+
+		for _, entry := range entries {
+			manifest, cacheDir, err := m.resolveRemoteEntry(ctx, entry)
+			if err != nil {
+				m.logger.Error("failed to resolve remote plugin", zap.String("id", entry.ID), zap.Error(err))
+				continue
+			}
+			if !m.cfg().IsPluginEnabled(manifest.ID) {
+				m.logger.Debug("remote plugin disabled", zap.String("id", manifest.ID))
+				continue
+			}
+			manifests = append(manifests, manifest)
+			paths[manifest.ID] = cacheDir
+		}
+	}
+
+	return manifests, paths
+}
+
+// RefreshRegistry re-pulls every RemoteSource's index and loads any plugin
+// that's new or whose declared version changed, without requiring a host
+// restart. A plugin already loaded at the declared version is left alone.
+func (m *Manager) RefreshRegistry(ctx context.Context) error {
+	manifests, paths := m.loadRemoteManifests(ctx)
+
+	sorted, err := m.sortByDependencies(manifests)
+	if err != nil {
+		return fmt.Errorf("dependency resolution: %w", err)
+	}
+
+	for _, manifest := range sorted {
+		loaded, exists := m.GetPlugin(manifest.ID)
+		if exists && loaded.Info.Manifest.Version.Compare(manifest.Version) == 0 {
+			continue
+		}
+		if exists {
+			if err := m.UnloadPlugin(manifest.ID); err != nil {
+				m.logger.Error("failed to unload outdated remote plugin", zap.String("id", manifest.ID), zap.Error(err))
+				continue
+			}
+		}
+		if err := m.loadPlugin(ctx, manifest, paths[manifest.ID]); err != nil {
+			m.logger.Error("failed to load remote plugin", zap.String("id", manifest.ID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func fetchRegistryIndex(ctx context.Context, src config.RemoteSource) ([]registryEntry, error) {
+	pubKey, err := verify.ParseEd25519PublicKey(src.PublicKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	data, err := verify.Fetch(ctx, http.DefaultClient, src.URL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch index: %w", err)
+	}
+
+	var index registryIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parse index: %w", err)
+	}
+
+	keys := []ed25519.PublicKey{pubKey}
+	verified := make([]registryEntry, 0, len(index.Plugins))
+	for _, entry := range index.Plugins {
+		sig, err := base64.StdEncoding.DecodeString(entry.Sig)
+		if err != nil {
+			return nil, fmt.Errorf("entry %s: decode signature: %w", entry.ID, err)
+		}
+		if !verify.Ed25519VerifyAny(keys, entry.signingMessage(), sig) {
+			return nil, fmt.Errorf("entry %s: signature verification failed", entry.ID)
+		}
+		verified = append(verified, entry)
+	}
+
+	return verified, nil
+}
+
+// resolveRemoteEntry downloads entry.WasmURL into
+// config.DataDir/_cache/<id>/<version>/plugin.wasm if it isn't already
+// cached with a matching sha256, then synthesizes the plugin.Manifest
+// loadPlugin expects, with EntryPoint relative to the returned cache
+// directory exactly like a local plugin.toml's entry_point is relative to
+// its plugin directory.
+func (m *Manager) resolveRemoteEntry(ctx context.Context, entry registryEntry) (*plugin.Manifest, string, error) {
+	cacheDir := filepath.Join(m.cfg().DataDir, remoteCacheDir, entry.ID, entry.Version)
+	wasmPath := filepath.Join(cacheDir, remoteWasmFile)
+
+	if !cachedFileMatches(wasmPath, entry.SHA256) {
+		if err := downloadAndVerify(ctx, entry.WasmURL, wasmPath, entry.SHA256); err != nil {
+			return nil, "", err
+		}
+	}
+
+	version, err := parsePluginVersion(entry.Version)
+	if err != nil {
+		return nil, "", fmt.Errorf("parse version: %w", err)
+	}
+
+	manifest := &plugin.Manifest{
+		ID:         entry.ID,
+		Name:       entry.ID,
+		Version:    version,
+		APIVersion: plugin.CurrentABIVersion,
+		EntryPoint: remoteWasmFile,
+		Limits:     plugin.DefaultResourceLimits(),
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, "", fmt.Errorf("synthesize manifest: %w", err)
+	}
+
+	return manifest, cacheDir, nil
+}
+
+// cachedFileMatches reports whether path already exists and hashes to
+// wantSHA256, letting resolveRemoteEntry skip a re-download on cache hits.
+func cachedFileMatches(path, wantSHA256 string) bool {
+	return verify.FileSHA256Matches(path, wantSHA256)
+}
+
+func downloadAndVerify(ctx context.Context, url, destPath, wantSHA256 string) error {
+	data, err := verify.Fetch(ctx, http.DefaultClient, url)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+
+	if got := verify.SHA256Hex(data); got != wantSHA256 {
+		return fmt.Errorf("download %s: sha256 mismatch: got %s, want %s", url, got, wantSHA256)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+
+	tmp := destPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write cached wasm: %w", err)
+	}
+	return os.Rename(tmp, destPath)
+}
+
+// parsePluginVersion parses a "major.minor.patch" registry version string
+// into a plugin.Version. Missing components default to 0, so a registry
+// may publish "1" or "1.2" as well as "1.2.3".
+func parsePluginVersion(s string) (plugin.Version, error) {
+	parts := strings.SplitN(s, ".", 3)
+	nums := [3]int{}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return plugin.Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		nums[i] = n
+	}
+	return plugin.Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
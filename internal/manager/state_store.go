@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+// stateStoreDir is the subdirectory of config.DataDir the manager uses for
+// its own bookkeeping, kept separate from per-plugin data directories
+// (config.DataDir/<plugin-id>) so a plugin ID can never collide with it.
+const stateStoreDir = "_manager"
+
+const stateStoreFile = "state.json"
+
+// persistedState is the on-disk shape of state.json: the last State each
+// plugin ID was left in by EnablePlugin/DisablePlugin, consulted by LoadAll
+// to restore enable state across a host restart.
+type persistedState struct {
+	Plugins map[string]plugin.State `json:"plugins"`
+}
+
+func (m *Manager) stateStorePath() string {
+	return filepath.Join(m.cfg().DataDir, stateStoreDir, stateStoreFile)
+}
+
+func (m *Manager) loadStateStore() (map[string]plugin.State, error) {
+	data, err := os.ReadFile(m.stateStorePath())
+	if os.IsNotExist(err) {
+		return map[string]plugin.State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state store: %w", err)
+	}
+
+	var ps persistedState
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return nil, fmt.Errorf("parse state store: %w", err)
+	}
+	if ps.Plugins == nil {
+		ps.Plugins = map[string]plugin.State{}
+	}
+	return ps.Plugins, nil
+}
+
+// writeStateStore replaces state.json in one write-then-rename so a crash
+// mid-write never leaves a truncated or torn file behind.
+func (m *Manager) writeStateStore(states map[string]plugin.State) error {
+	dir := filepath.Join(m.cfg().DataDir, stateStoreDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create state store directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(persistedState{Plugins: states}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal state store: %w", err)
+	}
+
+	path := m.stateStorePath()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write state store: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// persistState records id's LastKnownState to state.json, called by
+// EnablePlugin and DisablePlugin whenever they change a plugin's State.
+func (m *Manager) persistState(id string, state plugin.State) error {
+	states, err := m.loadStateStore()
+	if err != nil {
+		return err
+	}
+	states[id] = state
+	return m.writeStateStore(states)
+}
+
+// ResetState forgets id's persisted enable/disable state, so the next
+// LoadAll falls back to config.IsPluginEnabled's default instead of
+// restoring whatever state id was last left in. UnloadPlugin calls this
+// automatically since a removed plugin's stale state would otherwise
+// silently re-enable it if it's ever reinstalled under the same ID.
+func (m *Manager) ResetState(id string) error {
+	states, err := m.loadStateStore()
+	if err != nil {
+		return err
+	}
+	delete(states, id)
+	return m.writeStateStore(states)
+}
@@ -1,21 +1,116 @@
 package manager
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/config"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
 )
 
+// Storage is the per-plugin key/value store backing pkg/host's storage host
+// functions. Batch lets a caller group several writes into one fsync
+// instead of one per key, and Iterate lets a plugin scan its own namespace
+// by key prefix - neither was possible against the original Set-per-call,
+// rewrite-the-whole-file design.
 type Storage interface {
 	Get(pluginID, key string) ([]byte, bool, error)
 	Set(pluginID, key string, value []byte) error
 	Delete(pluginID, key string) error
 	Clear(pluginID string) error
+	Iterate(pluginID, prefix string) (Iterator, error)
+	Batch(pluginID string) StorageTx
 	Close() error
 }
 
+// Iterator walks a Storage namespace in key order. A caller must call Next
+// before the first Key/Value, same as sql.Rows.
+type Iterator interface {
+	Next() bool
+	Key() string
+	Value() []byte
+	Err() error
+}
+
+// StorageTx batches writes against one plugin's namespace; nothing is
+// visible to Get/Iterate until Commit succeeds.
+type StorageTx interface {
+	Set(key string, value []byte)
+	Delete(key string)
+	Commit() error
+}
+
+// NewStorage builds the Storage backend named by kind, matching
+// config.Config.StorageBackend, rooted at basePath for the backends that
+// persist to disk. Only "file" (the default) and "memory" are actually
+// implemented - both wired into Manager.New and exercised by every
+// host_storage_* call a plugin makes. "bolt" and "sqlite" are accepted
+// names that return a clear error rather than a confusing "unknown
+// backend" one, since an operator picking them read about them somewhere;
+// implementing either means vendoring go.etcd.io/bbolt or a sqlite
+// driver, which this module doesn't do today.
+func NewStorage(kind, basePath string) (Storage, error) {
+	switch kind {
+	case "", "file":
+		return NewFileStorage(basePath)
+	case "memory":
+		return NewMemoryStorage(), nil
+	case "bolt":
+		return nil, fmt.Errorf("storage backend %q is not implemented: requires vendoring go.etcd.io/bbolt", kind)
+	case "sqlite":
+		return nil, fmt.Errorf("storage backend %q is not implemented: requires vendoring a cgo or pure-Go sqlite driver", kind)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", kind)
+	}
+}
+
+// sliceIterator is the shared Iterator implementation for the in-memory
+// and file-backed stores below, both of which already hold every key for a
+// plugin in memory and can just sort+filter a snapshot up front.
+type sliceIterator struct {
+	keys   []string
+	values [][]byte
+	pos    int
+}
+
+func newSliceIterator(data map[string][]byte, prefix string) *sliceIterator {
+	it := &sliceIterator{pos: -1}
+	for k := range data {
+		if strings.HasPrefix(k, prefix) {
+			it.keys = append(it.keys, k)
+		}
+	}
+	sort.Strings(it.keys)
+	it.values = make([][]byte, len(it.keys))
+	for i, k := range it.keys {
+		it.values[i] = data[k]
+	}
+	return it
+}
+
+func (it *sliceIterator) Next() bool {
+	it.pos++
+	return it.pos < len(it.keys)
+}
+
+func (it *sliceIterator) Key() string   { return it.keys[it.pos] }
+func (it *sliceIterator) Value() []byte { return it.values[it.pos] }
+func (it *sliceIterator) Err() error    { return nil }
+
+// FileStorage persists each key as its own file under
+// basePath/pluginID/keys/<sha256(key)>, written via temp-file + fsync +
+// os.Rename, instead of the original design's single data.json rewritten on
+// every Set. A sha256 collision between two distinct keys in the same
+// plugin namespace would have one overwrite the other's file; at 256 bits
+// that's not a practical concern here.
 type FileStorage struct {
 	basePath string
 	mu       sync.RWMutex
@@ -49,20 +144,66 @@ func (s *FileStorage) loadAll() error {
 		}
 
 		pluginID := entry.Name()
-		data, err := os.ReadFile(filepath.Join(s.basePath, pluginID, "data.json"))
+		if err := s.loadPlugin(pluginID); err != nil {
+			return fmt.Errorf("load data for %s: %w", pluginID, err)
+		}
+	}
+	return nil
+}
+
+func (s *FileStorage) loadPlugin(pluginID string) error {
+	keysDir := filepath.Join(s.basePath, pluginID, "keys")
+	entries, err := os.ReadDir(keysDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s.migrateLegacyData(pluginID)
+		}
+		return err
+	}
+
+	pluginData := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(keysDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		key, value, err := decodeKeyFile(raw)
 		if err != nil {
-			if os.IsNotExist(err) {
-				continue
-			}
-			return fmt.Errorf("read data for %s: %w", pluginID, err)
+			return fmt.Errorf("key file %s: %w", entry.Name(), err)
 		}
+		pluginData[key] = value
+	}
+	s.data[pluginID] = pluginData
+	return nil
+}
 
-		var pluginData map[string][]byte
-		if err := json.Unmarshal(data, &pluginData); err != nil {
-			return fmt.Errorf("parse data for %s: %w", pluginID, err)
+// migrateLegacyData reads a pre-per-key-file data.json left over from
+// before this redesign, if one exists, so upgrading doesn't lose data. It's
+// left on disk rather than deleted; every key in it is persisted into the
+// new per-key layout here, and loadPlugin won't look at data.json again
+// once the keys directory exists.
+func (s *FileStorage) migrateLegacyData(pluginID string) error {
+	data, err := os.ReadFile(filepath.Join(s.basePath, pluginID, "data.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
 		}
-		s.data[pluginID] = pluginData
+		return err
 	}
+
+	var pluginData map[string][]byte
+	if err := json.Unmarshal(data, &pluginData); err != nil {
+		return fmt.Errorf("parse legacy data.json: %w", err)
+	}
+	for key, value := range pluginData {
+		if err := s.persistKey(pluginID, key, value); err != nil {
+			return fmt.Errorf("migrate legacy key %s: %w", key, err)
+		}
+	}
+	s.data[pluginID] = pluginData
 	return nil
 }
 
@@ -85,7 +226,7 @@ func (s *FileStorage) Set(pluginID, key string, value []byte) error {
 		s.data[pluginID] = make(map[string][]byte)
 	}
 	s.data[pluginID][key] = value
-	return s.persist(pluginID)
+	return s.persistKey(pluginID, key, value)
 }
 
 func (s *FileStorage) Delete(pluginID, key string) error {
@@ -94,7 +235,10 @@ func (s *FileStorage) Delete(pluginID, key string) error {
 
 	if pluginData, ok := s.data[pluginID]; ok {
 		delete(pluginData, key)
-		return s.persist(pluginID)
+	}
+	path := s.keyPath(pluginID, key)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
 	}
 	return nil
 }
@@ -107,31 +251,124 @@ func (s *FileStorage) Clear(pluginID string) error {
 	return os.RemoveAll(filepath.Join(s.basePath, pluginID))
 }
 
-func (s *FileStorage) persist(pluginID string) error {
-	pluginPath := filepath.Join(s.basePath, pluginID)
-	if err := os.MkdirAll(pluginPath, 0o755); err != nil {
+func (s *FileStorage) Iterate(pluginID, prefix string) (Iterator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return newSliceIterator(s.data[pluginID], prefix), nil
+}
+
+// Batch groups writes under one lock acquisition and one fsync per key
+// instead of per Set call, applied in Commit.
+func (s *FileStorage) Batch(pluginID string) StorageTx {
+	return &fileStorageTx{storage: s, pluginID: pluginID}
+}
+
+func (s *FileStorage) keyPath(pluginID, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.basePath, pluginID, "keys", hex.EncodeToString(sum[:]))
+}
+
+// persistKey writes key's envelope to a temp file, fsyncs it, then renames
+// it into place, so a crash mid-write leaves either the old file or nothing
+// - never a partially-written one.
+func (s *FileStorage) persistKey(pluginID, key string, value []byte) error {
+	path := s.keyPath(pluginID, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
 
-	data, err := json.Marshal(s.data[pluginID])
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(pluginPath, "data.json"), data, 0o644)
+	if _, err := f.Write(encodeKeyFile(key, value)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
 
 func (s *FileStorage) Close() error {
+	return nil
+}
+
+// fileStorageTx buffers Set/Delete calls and applies them to FileStorage
+// under a single lock in Commit.
+type fileStorageTx struct {
+	storage  *FileStorage
+	pluginID string
+	sets     map[string][]byte
+	deletes  []string
+}
+
+func (tx *fileStorageTx) Set(key string, value []byte) {
+	if tx.sets == nil {
+		tx.sets = make(map[string][]byte)
+	}
+	tx.sets[key] = value
+}
+
+func (tx *fileStorageTx) Delete(key string) {
+	tx.deletes = append(tx.deletes, key)
+}
+
+func (tx *fileStorageTx) Commit() error {
+	s := tx.storage
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for pluginID := range s.data {
-		if err := s.persist(pluginID); err != nil {
-			return fmt.Errorf("persist %s: %w", pluginID, err)
+	if s.data[tx.pluginID] == nil {
+		s.data[tx.pluginID] = make(map[string][]byte)
+	}
+
+	for _, key := range tx.deletes {
+		delete(s.data[tx.pluginID], key)
+		if err := os.Remove(s.keyPath(tx.pluginID, key)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+	for key, value := range tx.sets {
+		s.data[tx.pluginID][key] = value
+		if err := s.persistKey(tx.pluginID, key, value); err != nil {
+			return fmt.Errorf("set %s: %w", key, err)
 		}
 	}
 	return nil
 }
 
+// encodeKeyFile and decodeKeyFile frame a per-key file as a uint32 key
+// length, the key bytes, then the raw value bytes, so loadPlugin can recover
+// the original key from a sha256-named file without a separate index.
+func encodeKeyFile(key string, value []byte) []byte {
+	buf := make([]byte, 4+len(key)+len(value))
+	binary.LittleEndian.PutUint32(buf[:4], uint32(len(key)))
+	copy(buf[4:], key)
+	copy(buf[4+len(key):], value)
+	return buf
+}
+
+func decodeKeyFile(raw []byte) (string, []byte, error) {
+	if len(raw) < 4 {
+		return "", nil, fmt.Errorf("truncated key file")
+	}
+	keyLen := binary.LittleEndian.Uint32(raw[:4])
+	if uint64(4+keyLen) > uint64(len(raw)) {
+		return "", nil, fmt.Errorf("truncated key file")
+	}
+	key := string(raw[4 : 4+keyLen])
+	value := raw[4+keyLen:]
+	return key, value, nil
+}
+
 type MemoryStorage struct {
 	mu   sync.RWMutex
 	data map[string]map[string][]byte
@@ -181,4 +418,256 @@ func (s *MemoryStorage) Clear(pluginID string) error {
 	return nil
 }
 
+func (s *MemoryStorage) Iterate(pluginID, prefix string) (Iterator, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return newSliceIterator(s.data[pluginID], prefix), nil
+}
+
+func (s *MemoryStorage) Batch(pluginID string) StorageTx {
+	return &memoryStorageTx{storage: s, pluginID: pluginID}
+}
+
 func (s *MemoryStorage) Close() error { return nil }
+
+type memoryStorageTx struct {
+	storage  *MemoryStorage
+	pluginID string
+	sets     map[string][]byte
+	deletes  []string
+}
+
+func (tx *memoryStorageTx) Set(key string, value []byte) {
+	if tx.sets == nil {
+		tx.sets = make(map[string][]byte)
+	}
+	tx.sets[key] = value
+}
+
+func (tx *memoryStorageTx) Delete(key string) {
+	tx.deletes = append(tx.deletes, key)
+}
+
+func (tx *memoryStorageTx) Commit() error {
+	s := tx.storage
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data[tx.pluginID] == nil {
+		s.data[tx.pluginID] = make(map[string][]byte)
+	}
+	for _, key := range tx.deletes {
+		delete(s.data[tx.pluginID], key)
+	}
+	for key, value := range tx.sets {
+		s.data[tx.pluginID][key] = value
+	}
+	return nil
+}
+
+// ErrStorageQuotaExceeded reports that a Storage.Set or StorageTx.Commit
+// would push a plugin's cumulative key+value bytes past its effective
+// MaxStorageBytes.
+type ErrStorageQuotaExceeded struct {
+	PluginID  string
+	Limit     int64
+	Requested int64
+}
+
+func (e *ErrStorageQuotaExceeded) Error() string {
+	return fmt.Sprintf("plugin %s storage quota exceeded: requested %d bytes, limit %d bytes", e.PluginID, e.Requested, e.Limit)
+}
+
+// LimitsLookup resolves a plugin's declared plugin.ResourceLimits by ID.
+// QuotaStorage takes one instead of depending on Manager directly, since
+// Storage predates Manager ever wiring it up (see NewStorage).
+type LimitsLookup func(pluginID string) (plugin.ResourceLimits, bool)
+
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value))
+}
+
+// QuotaStorage enforces plugin.ResourceLimits.MaxStorageBytes (resolved
+// through Config.GetEffectiveLimits) on top of any Storage backend, tracking
+// cumulative key+value bytes per plugin in an in-memory counter. usagePath,
+// if non-empty, persists that counter on Close and reloads it in
+// NewQuotaStorage so a restart doesn't need to recount - Storage has no way
+// to enumerate every plugin ID on its own, so without a persisted snapshot
+// the counter starts back at zero and only reflects writes made since.
+type QuotaStorage struct {
+	inner     Storage
+	config    *config.Config
+	limitsFor LimitsLookup
+	usagePath string
+
+	mu    sync.Mutex
+	usage map[string]int64
+}
+
+func NewQuotaStorage(inner Storage, cfg *config.Config, limitsFor LimitsLookup, usagePath string) (*QuotaStorage, error) {
+	q := &QuotaStorage{
+		inner:     inner,
+		config:    cfg,
+		limitsFor: limitsFor,
+		usagePath: usagePath,
+		usage:     make(map[string]int64),
+	}
+	if usagePath != "" {
+		if err := q.loadUsage(); err != nil {
+			return nil, fmt.Errorf("load storage quota snapshot: %w", err)
+		}
+	}
+	return q, nil
+}
+
+func (q *QuotaStorage) loadUsage() error {
+	data, err := os.ReadFile(q.usagePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &q.usage)
+}
+
+func (q *QuotaStorage) effectiveLimits(pluginID string) plugin.ResourceLimits {
+	declared, _ := q.limitsFor(pluginID)
+	return q.config.GetEffectiveLimits(declared)
+}
+
+func (q *QuotaStorage) Get(pluginID, key string) ([]byte, bool, error) {
+	return q.inner.Get(pluginID, key)
+}
+
+func (q *QuotaStorage) Set(pluginID, key string, value []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delta := entrySize(key, value)
+	if existing, ok, err := q.inner.Get(pluginID, key); err == nil && ok {
+		delta -= entrySize(key, existing)
+	}
+
+	projected := q.usage[pluginID] + delta
+	if limit := q.effectiveLimits(pluginID).MaxStorageBytes; limit > 0 && projected > limit {
+		return &ErrStorageQuotaExceeded{PluginID: pluginID, Limit: limit, Requested: projected}
+	}
+
+	if err := q.inner.Set(pluginID, key, value); err != nil {
+		return err
+	}
+	q.usage[pluginID] = projected
+	return nil
+}
+
+func (q *QuotaStorage) Delete(pluginID, key string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	existing, ok, err := q.inner.Get(pluginID, key)
+	if err != nil {
+		return err
+	}
+	if err := q.inner.Delete(pluginID, key); err != nil {
+		return err
+	}
+	if ok {
+		q.usage[pluginID] = max(0, q.usage[pluginID]-entrySize(key, existing))
+	}
+	return nil
+}
+
+func (q *QuotaStorage) Clear(pluginID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.usage, pluginID)
+	return q.inner.Clear(pluginID)
+}
+
+func (q *QuotaStorage) Iterate(pluginID, prefix string) (Iterator, error) {
+	return q.inner.Iterate(pluginID, prefix)
+}
+
+func (q *QuotaStorage) Batch(pluginID string) StorageTx {
+	return &quotaStorageTx{quota: q, pluginID: pluginID, inner: q.inner.Batch(pluginID)}
+}
+
+// Close persists the usage counter snapshot (if usagePath is set) before
+// closing the wrapped backend.
+func (q *QuotaStorage) Close() error {
+	q.mu.Lock()
+	if q.usagePath != "" {
+		data, err := json.Marshal(q.usage)
+		if err != nil {
+			q.mu.Unlock()
+			return fmt.Errorf("marshal storage quota snapshot: %w", err)
+		}
+		if err := os.WriteFile(q.usagePath, data, 0o644); err != nil {
+			q.mu.Unlock()
+			return fmt.Errorf("write storage quota snapshot: %w", err)
+		}
+	}
+	q.mu.Unlock()
+
+	return q.inner.Close()
+}
+
+// quotaStorageTx mirrors fileStorageTx/memoryStorageTx: Set/Delete only
+// buffer, and the quota check (plus the underlying write) happens in Commit.
+type quotaStorageTx struct {
+	quota    *QuotaStorage
+	pluginID string
+	inner    StorageTx
+	sets     map[string][]byte
+	deletes  []string
+}
+
+func (tx *quotaStorageTx) Set(key string, value []byte) {
+	if tx.sets == nil {
+		tx.sets = make(map[string][]byte)
+	}
+	tx.sets[key] = value
+}
+
+func (tx *quotaStorageTx) Delete(key string) {
+	tx.deletes = append(tx.deletes, key)
+}
+
+func (tx *quotaStorageTx) Commit() error {
+	q := tx.quota
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delta := int64(0)
+	for _, key := range tx.deletes {
+		if existing, ok, err := q.inner.Get(tx.pluginID, key); err == nil && ok {
+			delta -= entrySize(key, existing)
+		}
+	}
+	for key, value := range tx.sets {
+		delta += entrySize(key, value)
+		if existing, ok, err := q.inner.Get(tx.pluginID, key); err == nil && ok {
+			delta -= entrySize(key, existing)
+		}
+	}
+
+	projected := q.usage[tx.pluginID] + delta
+	if limit := q.effectiveLimits(tx.pluginID).MaxStorageBytes; limit > 0 && projected > limit {
+		return &ErrStorageQuotaExceeded{PluginID: tx.pluginID, Limit: limit, Requested: projected}
+	}
+
+	for key, value := range tx.sets {
+		tx.inner.Set(key, value)
+	}
+	for _, key := range tx.deletes {
+		tx.inner.Delete(key)
+	}
+	if err := tx.inner.Commit(); err != nil {
+		return err
+	}
+	q.usage[tx.pluginID] = projected
+	return nil
+}
@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptedStorageRoundTrip(t *testing.T) {
+	s := NewEncryptedStorage(NewMemoryStorage(), []byte("test-master-key"))
+
+	if err := s.Set("pluginA", "k", []byte("hello")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, ok, err := s.Get("pluginA", "k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !ok {
+		t.Fatalf("Get returned ok=false for a key that was just Set")
+	}
+	if !bytes.Equal(got, []byte("hello")) {
+		t.Errorf("Get = %q, want %q", got, "hello")
+	}
+}
+
+func TestEncryptedStorageStoresCiphertextNotPlaintext(t *testing.T) {
+	inner := NewMemoryStorage()
+	s := NewEncryptedStorage(inner, []byte("test-master-key"))
+
+	plaintext := []byte("super secret value")
+	if err := s.Set("pluginA", "k", plaintext); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	raw, ok, err := inner.Get("pluginA", "k")
+	if err != nil || !ok {
+		t.Fatalf("inner.Get: ok=%v err=%v", ok, err)
+	}
+	if bytes.Contains(raw, plaintext) {
+		t.Errorf("plaintext found verbatim in the underlying backend's stored bytes")
+	}
+}
+
+func TestEncryptedStorageDifferentPluginsCannotDecryptEachOther(t *testing.T) {
+	inner := NewMemoryStorage()
+	s := NewEncryptedStorage(inner, []byte("test-master-key"))
+
+	if err := s.Set("pluginA", "k", []byte("a's secret")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	sealed, ok, err := inner.Get("pluginA", "k")
+	if err != nil || !ok {
+		t.Fatalf("inner.Get: ok=%v err=%v", ok, err)
+	}
+	// Smuggle pluginA's ciphertext into pluginB's namespace: it should be
+	// derived from a different HKDF subkey and fail to decrypt.
+	if err := inner.Set("pluginB", "k", sealed); err != nil {
+		t.Fatalf("inner.Set: %v", err)
+	}
+
+	if _, _, err := s.Get("pluginB", "k"); err == nil {
+		t.Errorf("Get succeeded decrypting pluginA's ciphertext under pluginB's subkey, want an error")
+	}
+}
+
+func TestEncryptedStorageTamperedCiphertextFailsToDecrypt(t *testing.T) {
+	inner := NewMemoryStorage()
+	s := NewEncryptedStorage(inner, []byte("test-master-key"))
+
+	if err := s.Set("pluginA", "k", []byte("hello")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	sealed, _, _ := inner.Get("pluginA", "k")
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 0xFF
+	if err := inner.Set("pluginA", "k", tampered); err != nil {
+		t.Fatalf("inner.Set: %v", err)
+	}
+
+	if _, _, err := s.Get("pluginA", "k"); err == nil {
+		t.Errorf("Get succeeded against a tampered ciphertext, want a GCM authentication error")
+	}
+}
+
+func TestEncryptedStorageBatch(t *testing.T) {
+	s := NewEncryptedStorage(NewMemoryStorage(), []byte("test-master-key"))
+
+	tx := s.Batch("pluginA")
+	tx.Set("k1", []byte("v1"))
+	tx.Set("k2", []byte("v2"))
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	for key, want := range map[string]string{"k1": "v1", "k2": "v2"} {
+		got, ok, err := s.Get("pluginA", key)
+		if err != nil || !ok {
+			t.Fatalf("Get(%q): ok=%v err=%v", key, ok, err)
+		}
+		if string(got) != want {
+			t.Errorf("Get(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestHKDFSHA256Deterministic(t *testing.T) {
+	a, err := hkdfSHA256([]byte("secret"), []byte("pluginA"), 32)
+	if err != nil {
+		t.Fatalf("hkdfSHA256: %v", err)
+	}
+	b, err := hkdfSHA256([]byte("secret"), []byte("pluginA"), 32)
+	if err != nil {
+		t.Fatalf("hkdfSHA256: %v", err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Errorf("hkdfSHA256 is not deterministic for identical inputs")
+	}
+
+	c, err := hkdfSHA256([]byte("secret"), []byte("pluginB"), 32)
+	if err != nil {
+		t.Fatalf("hkdfSHA256: %v", err)
+	}
+	if bytes.Equal(a, c) {
+		t.Errorf("hkdfSHA256 produced the same subkey for different info (plugin ID)")
+	}
+	if len(a) != 32 {
+		t.Errorf("len(subkey) = %d, want 32", len(a))
+	}
+}
@@ -0,0 +1,137 @@
+package manager
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	extism "github.com/extism/go-sdk"
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+// maxSupervisorBackoff caps the exponential backoff restartWithBackoff waits
+// between rebuild attempts, regardless of how high BackoffBaseMs or the
+// attempt count go.
+const maxSupervisorBackoff = 30 * time.Second
+
+// superviseCrash marks loaded StateError after a handle_event/plugin_init
+// failure (already recorded on loaded.Info.Metrics by the caller) and, if
+// its manifest opts into Limits.Supervise, kicks off a restart attempt.
+// Called via "go m.superviseCrash(...)" from createEventHandler, since
+// loaded.mu is still held by the caller at that point.
+func (m *Manager) superviseCrash(loaded *LoadedPlugin, causeErr error) {
+	loaded.mu.Lock()
+	wasEnabled := loaded.Info.State == plugin.StateEnabled
+	previous := loaded.Info.State
+	loaded.Info.State = plugin.StateError
+	id := loaded.Info.Manifest.ID
+	limits := loaded.Info.Manifest.Limits
+	loaded.mu.Unlock()
+
+	m.lifecycle.Emit(events.LifecycleEvent{
+		Type: events.LifecyclePluginErrored, PluginID: plugin.PluginID(id),
+		Previous: previous, Next: plugin.StateError, Time: time.Now(), Err: causeErr,
+	})
+
+	if !limits.Supervise {
+		return
+	}
+	m.restartWithBackoff(loaded, limits, wasEnabled)
+}
+
+// restartWithBackoff rebuilds loaded's Extism instance up to
+// limits.MaxRestarts times, waiting BackoffBaseMs*2^(attempt-1) (capped at
+// maxSupervisorBackoff) between attempts. On success it re-enables the
+// plugin if it was StateEnabled when it crashed.
+func (m *Manager) restartWithBackoff(loaded *LoadedPlugin, limits plugin.ResourceLimits, reenable bool) {
+	id := loaded.Info.Manifest.ID
+
+	maxRestarts := limits.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = plugin.DefaultResourceLimits().MaxRestarts
+	}
+	base := time.Duration(limits.BackoffBaseMs) * time.Millisecond
+	if base <= 0 {
+		base = time.Duration(plugin.DefaultResourceLimits().BackoffBaseMs) * time.Millisecond
+	}
+
+	for attempt := 1; attempt <= maxRestarts; attempt++ {
+		backoff := min(base*time.Duration(math.Pow(2, float64(attempt-1))), maxSupervisorBackoff)
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		m.lifecycle.Emit(events.LifecycleEvent{
+			Type: events.LifecyclePluginRestarting, PluginID: plugin.PluginID(id), Time: time.Now(),
+		})
+		err := m.rebuildInstance(loaded)
+		restarted := events.LifecycleEvent{
+			Type: events.LifecyclePluginRestarted, PluginID: plugin.PluginID(id), Time: time.Now(), Err: err,
+		}
+		if err == nil {
+			restarted.Previous, restarted.Next = plugin.StateError, plugin.StateLoaded
+		}
+		m.lifecycle.Emit(restarted)
+
+		if err != nil {
+			m.logger.Warn("plugin restart attempt failed",
+				zap.String("id", id), zap.Int("attempt", attempt), zap.Int("max_restarts", maxRestarts), zap.Error(err))
+			continue
+		}
+
+		loaded.Info.Metrics.RecordRestart()
+		m.logger.Info("plugin restarted", zap.String("id", id), zap.Int("attempt", attempt))
+
+		if reenable {
+			if err := m.EnablePlugin(id); err != nil {
+				m.logger.Warn("failed to re-enable plugin after restart", zap.String("id", id), zap.Error(err))
+			}
+		}
+		return
+	}
+
+	m.logger.Error("plugin exhausted restart attempts, leaving in error state",
+		zap.String("id", id), zap.Int("max_restarts", maxRestarts))
+}
+
+// rebuildInstance replaces loaded's Extism plugin with a freshly created one
+// from the same wasm file, re-runs plugin_init, and re-registers its event
+// subscriptions - the same sequence loadPlugin follows on first load.
+func (m *Manager) rebuildInstance(loaded *LoadedPlugin) error {
+	loaded.mu.Lock()
+	defer loaded.mu.Unlock()
+
+	limits := m.cfg().GetEffectiveLimits(loaded.Info.Manifest.Limits)
+	extismManifest := extism.Manifest{
+		Wasm: []extism.Wasm{
+			extism.WasmFile{Path: loaded.Info.WASMPath},
+		},
+		Memory: &extism.ManifestMemory{
+			MaxPages: uint32(limits.MaxMemoryMB * 16),
+		},
+	}
+
+	instance, err := extism.NewPlugin(m.ctx, extismManifest, extism.PluginConfig{EnableWasi: true}, m.createHostFunctions(loaded))
+	if err != nil {
+		return fmt.Errorf("recreate WASM instance: %w", err)
+	}
+
+	if _, _, err := instance.Call("plugin_init", nil); err != nil {
+		instance.Close()
+		return fmt.Errorf("plugin_init after restart: %w", err)
+	}
+
+	loaded.Instance.Close()
+	loaded.Instance = instance
+	loaded.Info.State = plugin.StateLoaded
+
+	m.dispatcher.Unsubscribe(loaded.Info.Manifest.ID)
+	m.registerEventHandlers(loaded)
+	return nil
+}
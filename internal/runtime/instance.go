@@ -11,6 +11,7 @@ import (
 
 	"github.com/EinBexiii/dragonfly-wasm/pkg/config"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/events"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events/proto"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/host"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
 )
@@ -48,6 +49,7 @@ func NewInstance(info *plugin.Info, wasmBytes []byte, cfg *config.Config, hostFu
 		return nil, fmt.Errorf("create extism plugin: %w", err)
 	}
 	inst.plugin = p
+
 	return inst, nil
 }
 
@@ -99,6 +101,7 @@ func (i *Instance) HandleEvent(ctx context.Context, event plugin.EventType, data
 		return nil, nil
 	}
 
+	start := time.Now()
 	result, err := i.Call(ctx, "on_"+string(event), data)
 	if err != nil {
 		return nil, err
@@ -106,6 +109,7 @@ func (i *Instance) HandleEvent(ctx context.Context, event plugin.EventType, data
 
 	eventResult := parseEventResult(result)
 	i.info.Metrics.RecordEvent(event, eventResult.Cancelled)
+	i.info.Metrics.RecordEventDuration(event, time.Since(start))
 	return eventResult, nil
 }
 
@@ -169,6 +173,49 @@ func (i *Instance) Close() error {
 	return nil
 }
 
+// HealthCheck calls the plugin's optional health_check export, treating its
+// absence as healthy (not every plugin needs one) and a timeout or non-nil
+// error the same way Call does for any other export: a reportable failure
+// for Supervisor to act on.
+func (i *Instance) HealthCheck(ctx context.Context) error {
+	i.mu.RLock()
+	exists := i.plugin.FunctionExists("health_check")
+	i.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	_, err := i.Call(ctx, "health_check", nil)
+	return err
+}
+
+// Rebuild closes the current extism plugin and recreates it from the same
+// manifest (and therefore the same wasmBytes NewInstance was given), and
+// runs plugin_init on the new plugin before swapping it in. The
+// Instance value itself - and so every Pool channel entry and outstanding
+// pointer to it - stays valid; only the extism.Plugin underneath is
+// swapped out.
+func (i *Instance) Rebuild(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	p, err := extism.NewPlugin(ctx, i.manifest, extism.PluginConfig{EnableWasi: true}, i.hostFunctions.CreateHostFunctions(i.info.Manifest.ID))
+	if err != nil {
+		return fmt.Errorf("recreate extism plugin: %w", err)
+	}
+
+	if _, _, err := p.Call("plugin_init", nil); err != nil {
+		p.Close()
+		return fmt.Errorf("plugin_init after rebuild: %w", err)
+	}
+
+	i.plugin.Close()
+	i.plugin = p
+	i.info.State = plugin.StateLoaded
+	i.logger.Info("plugin instance rebuilt")
+	return nil
+}
+
 func (i *Instance) IsEnabled() bool {
 	i.mu.RLock()
 	defer i.mu.RUnlock()
@@ -182,7 +229,7 @@ func (i *Instance) State() plugin.State {
 }
 
 func parseEventResult(data []byte) *events.EventResult {
-	result := &events.EventResult{Modifications: make(map[string]string)}
+	result := &events.EventResult{Patch: proto.NewPatch()}
 	if len(data) > 0 && data[0] == 1 {
 		result.Cancelled = true
 	}
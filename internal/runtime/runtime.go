@@ -1,3 +1,20 @@
+// Package runtime implements a Pool/Instance lifecycle for plugins hosted
+// through pkg/host's FunctionProvider, parked alongside internal/manager's
+// own Manager/LoadedPlugin lifecycle rather than wired into it - see
+// pkg/host's package doc for why. NewPool/NewInstance have no callers
+// outside this package and its tests, and internal/manager does not
+// construct a Pool.
+//
+// This package previously also persisted PersistedInstanceState to its own
+// state.json and warm-restored from it on startup (state_store.go,
+// restore.go), ran its own crash supervisor (supervisor.go), and gave
+// Instance a bidirectional stream to the guest (stream.go, on top of
+// pkg/host's StreamManager). All three duplicated functionality
+// internal/manager already has wired into the live LoadedPlugin path -
+// restoreEnableState and supervisor.go there, and host_storage_* as the
+// closest analogue streaming never got - for a Pool nothing constructs, so
+// all three were removed rather than brought up to parity; see git history
+// for StateStore/Restore/Supervisor/RestartPolicy/OpenStream.
 package runtime
 
 import (
@@ -0,0 +1,216 @@
+// Package chat implements Minecraft's JSON text component format, the
+// §-prefixed legacy encoding used on the Bedrock wire, and conversions
+// between the two, so plugins can send styled, translatable, and clickable
+// chat instead of plain strings.
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Color is one of the sixteen legacy formatting colors.
+type Color string
+
+const (
+	ColorBlack       Color = "black"
+	ColorDarkBlue    Color = "dark_blue"
+	ColorDarkGreen   Color = "dark_green"
+	ColorDarkAqua    Color = "dark_aqua"
+	ColorDarkRed     Color = "dark_red"
+	ColorDarkPurple  Color = "dark_purple"
+	ColorGold        Color = "gold"
+	ColorGray        Color = "gray"
+	ColorDarkGray    Color = "dark_gray"
+	ColorBlue        Color = "blue"
+	ColorGreen       Color = "green"
+	ColorAqua        Color = "aqua"
+	ColorRed         Color = "red"
+	ColorLightPurple Color = "light_purple"
+	ColorYellow      Color = "yellow"
+	ColorWhite       Color = "white"
+)
+
+// legacyCodes maps each Color to its § formatting code.
+var legacyCodes = map[Color]byte{
+	ColorBlack: '0', ColorDarkBlue: '1', ColorDarkGreen: '2', ColorDarkAqua: '3',
+	ColorDarkRed: '4', ColorDarkPurple: '5', ColorGold: '6', ColorGray: '7',
+	ColorDarkGray: '8', ColorBlue: '9', ColorGreen: 'a', ColorAqua: 'b',
+	ColorRed: 'c', ColorLightPurple: 'd', ColorYellow: 'e', ColorWhite: 'f',
+}
+
+var codeColors = func() map[byte]Color {
+	m := make(map[byte]Color, len(legacyCodes))
+	for color, code := range legacyCodes {
+		m[code] = color
+	}
+	return m
+}()
+
+// ClickAction identifies what a ClickEvent does when its component is
+// clicked.
+type ClickAction string
+
+const (
+	ClickRunCommand      ClickAction = "run_command"
+	ClickSuggestCommand  ClickAction = "suggest_command"
+	ClickOpenURL         ClickAction = "open_url"
+	ClickCopyToClipboard ClickAction = "copy_to_clipboard"
+)
+
+// ClickEvent runs Action with Value when a component is clicked.
+type ClickEvent struct {
+	Action ClickAction `json:"action"`
+	Value  string      `json:"value"`
+}
+
+// HoverAction identifies what a HoverEvent shows when its component is
+// hovered.
+type HoverAction string
+
+const HoverShowText HoverAction = "show_text"
+
+// HoverEvent shows Contents when a component is hovered.
+type HoverEvent struct {
+	Action   HoverAction `json:"action"`
+	Contents string      `json:"contents"`
+}
+
+// Component is a single node of Minecraft's JSON text component tree.
+// Exactly one of Text or Translate should be set; With supplies the
+// translation's substitution arguments when Translate is used.
+type Component struct {
+	Text      string      `json:"text,omitempty"`
+	Translate string      `json:"translate,omitempty"`
+	With      []Component `json:"with,omitempty"`
+
+	Color      Color `json:"color,omitempty"`
+	Bold       bool  `json:"bold,omitempty"`
+	Italic     bool  `json:"italic,omitempty"`
+	Underlined bool  `json:"underlined,omitempty"`
+	Obfuscated bool  `json:"obfuscated,omitempty"`
+
+	ClickEvent *ClickEvent `json:"clickEvent,omitempty"`
+	HoverEvent *HoverEvent `json:"hoverEvent,omitempty"`
+
+	Extra []Component `json:"extra,omitempty"`
+}
+
+// Text returns a plain, unformatted text component.
+func Text(s string) Component { return Component{Text: s} }
+
+// Translate returns a translatable component resolved client-side from key,
+// with with as its substitution arguments.
+func Translate(key string, with ...Component) Component {
+	return Component{Translate: key, With: with}
+}
+
+// Encode renders c as Minecraft's JSON text format.
+func (c Component) Encode() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// Serialize renders c and its Extra children as a single §-formatted legacy
+// string, for APIs that only accept plain text (e.g. player.Player.Message).
+// A §r reset is emitted between siblings so one child's style never bleeds
+// into the next.
+func (c Component) Serialize() string {
+	var b strings.Builder
+	c.writeLegacy(&b)
+	return b.String()
+}
+
+func (c Component) writeLegacy(b *strings.Builder) {
+	if code, ok := legacyCodes[c.Color]; ok {
+		b.WriteByte('§')
+		b.WriteByte(code)
+	}
+	if c.Bold {
+		b.WriteString("§l")
+	}
+	if c.Italic {
+		b.WriteString("§o")
+	}
+	if c.Underlined {
+		b.WriteString("§n")
+	}
+	if c.Obfuscated {
+		b.WriteString("§k")
+	}
+
+	if c.Translate != "" {
+		b.WriteString(c.Translate)
+	} else {
+		b.WriteString(c.Text)
+	}
+
+	for _, extra := range c.Extra {
+		b.WriteString("§r")
+		extra.writeLegacy(b)
+	}
+}
+
+// ParseLegacy converts a §-formatted legacy string into a component tree,
+// starting a new node each time the active formatting changes so the result
+// round-trips through Serialize.
+func ParseLegacy(s string) Component {
+	root := Component{}
+	hasRoot := false
+	current := Component{}
+
+	var segment strings.Builder
+	flush := func() {
+		if segment.Len() == 0 {
+			return
+		}
+		node := current
+		node.Text = segment.String()
+		if !hasRoot {
+			root, hasRoot = node, true
+		} else {
+			root.Extra = append(root.Extra, node)
+		}
+		segment.Reset()
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '§' || i+1 >= len(runes) {
+			segment.WriteRune(runes[i])
+			continue
+		}
+
+		code := byte(runes[i+1])
+		if color, ok := codeColors[code]; ok {
+			flush()
+			current = Component{Color: color}
+			i++
+			continue
+		}
+		switch code {
+		case 'l':
+			flush()
+			current.Bold = true
+		case 'o':
+			flush()
+			current.Italic = true
+		case 'n':
+			flush()
+			current.Underlined = true
+		case 'k':
+			flush()
+			current.Obfuscated = true
+		case 'r':
+			flush()
+			current = Component{}
+		default:
+			// Not a recognized formatting code: keep the § literally and
+			// let the next rune (the would-be code) be read as plain text.
+			segment.WriteRune('§')
+			continue
+		}
+		i++
+	}
+	flush()
+	return root
+}
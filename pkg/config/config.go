@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
@@ -9,6 +11,7 @@ import (
 	"github.com/pelletier/go-toml/v2"
 
 	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/pluginregistry"
 )
 
 type Config struct {
@@ -21,12 +24,63 @@ type Config struct {
 	Security        SecurityConfig        `toml:"security"`
 	Logging         LoggingConfig         `toml:"logging"`
 	Performance     PerformanceConfig     `toml:"performance"`
+	RemoteSources   []RemoteSource        `toml:"remote_sources"`
+
+	// Plugins declares individual plugin installs the registry subsystem
+	// should keep in sync: each entry pins one plugin to a URL + sha256
+	// (and, optionally, a detached signature), unlike RemoteSources which
+	// resolves plugins indirectly through a signed index.json.
+	Plugins []pluginregistry.PluginSource `toml:"plugins"`
+
+	// DevMode gates Manager.WatchDevPlugin: hot-reloading a plugin on every
+	// WASM file write is only ever appropriate on a developer's own machine.
+	DevMode bool `toml:"dev_mode"`
+
+	// StorageBackend picks the Storage implementation behind plugin
+	// key/value data. Only "file" (the default) and "memory" are
+	// implemented; "bolt" and "sqlite" validate here but NewStorage
+	// rejects them at startup with a "not implemented" error, since
+	// neither has a vendored driver in this module yet.
+	StorageBackend string `toml:"storage_backend"`
+
+	// OCI enables installing/upgrading plugins by reference from an OCI
+	// registry, in addition to PluginDir discovery, Plugins and
+	// RemoteSources.
+	OCI OCIConfig `toml:"oci"`
+}
+
+// OCIConfig enables Manager.InstallPluginRef/UpgradePluginRef/
+// RollbackPluginRef, which resolve a "repo/name:tag" reference against an
+// OCI registry through a local content-addressable store instead of
+// requiring the operator to unpack a plugin under PluginDir by hand.
+type OCIConfig struct {
+	Enabled bool `toml:"enabled"`
+
+	// StoreDir is where pulled blobs and the alias/history index are kept,
+	// relative to nothing in particular - like CacheDir and PluginDir it's
+	// taken as given, typically an absolute path set by the embedder.
+	StoreDir string `toml:"store_dir"`
+}
+
+// RemoteSource points the manager's registry client at an index of
+// remotely-hosted plugins. PublicKeyPEM is the Ed25519 public key (PEM
+// block) every entry in URL's index must be signed with; if any entry's
+// signature doesn't verify against it, the whole index is skipped and none
+// of its entries are loaded.
+type RemoteSource struct {
+	URL          string `toml:"url"`
+	PublicKeyPEM string `toml:"public_key_pem"`
 }
 
 type SecurityConfig struct {
 	RequireSignedPlugins bool     `toml:"require_signed_plugins"`
 	SandboxMode          bool     `toml:"sandbox_mode"`
 	TrustedPublicKeys    []string `toml:"trusted_public_keys"`
+
+	// StorageEncryptionKeyFile, if set, names a file holding the raw master
+	// key manager.NewEncryptedStorage derives per-plugin subkeys from. Left
+	// empty, plugin storage is written unencrypted.
+	StorageEncryptionKeyFile string `toml:"storage_encryption_key_file"`
 }
 
 type LoggingConfig struct {
@@ -66,13 +120,15 @@ func DefaultRuntimeConfig() RuntimeConfig {
 
 func DefaultConfig() Config {
 	return Config{
-		PluginDir:     "plugins",
-		DataDir:       "plugin_data",
-		DefaultLimits: plugin.DefaultResourceLimits(),
+		PluginDir:      "plugins",
+		DataDir:        "plugin_data",
+		StorageBackend: "file",
+		DefaultLimits:  plugin.DefaultResourceLimits(),
 		GlobalLimits: plugin.ResourceLimits{
-			MaxMemoryMB:    256,
-			MaxExecutionMs: 1000,
-			MaxFuel:        10_000_000,
+			MaxMemoryMB:     256,
+			MaxExecutionMs:  1000,
+			MaxFuel:         10_000_000,
+			MaxStorageBytes: 256 << 20,
 		},
 		Security: SecurityConfig{
 			SandboxMode: true,
@@ -89,6 +145,9 @@ func DefaultConfig() Config {
 			EventQueueSize:        1000,
 			WorkerCount:           4,
 		},
+		OCI: OCIConfig{
+			StoreDir: "plugin_store",
+		},
 	}
 }
 
@@ -124,6 +183,25 @@ func (c *Config) Save(path string) error {
 	return os.WriteFile(path, data, 0o644)
 }
 
+// Validate reports whether c is sane enough to load, checked by Load's
+// callers and required by Watch before swapping a reloaded Config in: a
+// file that merely parses as TOML but names an unknown storage backend (a
+// typo mid-edit, say) shouldn't take effect.
+func (c *Config) Validate() error {
+	if c.PluginDir == "" {
+		return errors.New("plugin_dir is required")
+	}
+	if c.DataDir == "" {
+		return errors.New("data_dir is required")
+	}
+	switch c.StorageBackend {
+	case "", "file", "memory", "bolt", "sqlite":
+	default:
+		return fmt.Errorf("unknown storage_backend %q", c.StorageBackend)
+	}
+	return nil
+}
+
 func (c *Config) IsPluginEnabled(id string) bool {
 	if slices.Contains(c.DisabledPlugins, id) {
 		return false
@@ -144,10 +222,14 @@ func (c *Config) GetEffectiveLimits(limits plugin.ResourceLimits) plugin.Resourc
 	if limits.MaxFuel == 0 {
 		limits.MaxFuel = c.DefaultLimits.MaxFuel
 	}
+	if limits.MaxStorageBytes == 0 {
+		limits.MaxStorageBytes = c.DefaultLimits.MaxStorageBytes
+	}
 
 	limits.MaxMemoryMB = min(limits.MaxMemoryMB, c.GlobalLimits.MaxMemoryMB)
 	limits.MaxExecutionMs = min(limits.MaxExecutionMs, c.GlobalLimits.MaxExecutionMs)
 	limits.MaxFuel = min(limits.MaxFuel, c.GlobalLimits.MaxFuel)
+	limits.MaxStorageBytes = min(limits.MaxStorageBytes, c.GlobalLimits.MaxStorageBytes)
 
 	return limits
 }
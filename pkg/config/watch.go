@@ -0,0 +1,216 @@
+package config
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// watchPollInterval and watchDebounce control how Watch notices and
+// coalesces config file writes. fsnotify isn't vendored in this module (and
+// this sandbox has no network access to add it), so this polls os.Stat's
+// ModTime as a stand-in for an inotify/kqueue watch - the same pattern
+// internal/manager's WatchDevPlugin uses for hot-reloading plugin WASM
+// files; swap the body of Watcher.run for a real fsnotify.Watcher once that
+// dependency is available.
+const (
+	watchPollInterval = 250 * time.Millisecond
+	watchDebounce     = 250 * time.Millisecond
+)
+
+// ConfigDiff describes what changed between two successfully-loaded and
+// validated Configs, as reported on Watcher.Changes().
+type ConfigDiff struct {
+	NewlyEnabled  []string
+	NewlyDisabled []string
+
+	DefaultLimitsChanged bool
+	GlobalLimitsChanged  bool
+
+	Previous *Config
+	Current  *Config
+}
+
+// IsZero reports whether d describes no observable change, which Watcher
+// uses to skip emitting a diff for a reload that re-wrote the file with
+// identical content.
+func (d ConfigDiff) IsZero() bool {
+	return len(d.NewlyEnabled) == 0 && len(d.NewlyDisabled) == 0 &&
+		!d.DefaultLimitsChanged && !d.GlobalLimitsChanged
+}
+
+// diffConfigs compares a plugin ID's IsPluginEnabled result across old and
+// next for every ID either config mentions. It can't detect a plugin that
+// becomes implicitly enabled/disabled purely by EnabledPlugins going from
+// empty to non-empty without that plugin's ID appearing in either list -
+// Config has no view of what plugins actually exist on disk, only Manager
+// does, so ApplyConfig re-derives enablement per already-loaded plugin
+// directly rather than relying solely on this diff.
+func diffConfigs(old, next *Config) ConfigDiff {
+	diff := ConfigDiff{Previous: old, Current: next}
+
+	seen := make(map[string]struct{})
+	for _, id := range old.EnabledPlugins {
+		seen[id] = struct{}{}
+	}
+	for _, id := range old.DisabledPlugins {
+		seen[id] = struct{}{}
+	}
+	for _, id := range next.EnabledPlugins {
+		seen[id] = struct{}{}
+	}
+	for _, id := range next.DisabledPlugins {
+		seen[id] = struct{}{}
+	}
+
+	for id := range seen {
+		wasEnabled := old.IsPluginEnabled(id)
+		isEnabled := next.IsPluginEnabled(id)
+		switch {
+		case !wasEnabled && isEnabled:
+			diff.NewlyEnabled = append(diff.NewlyEnabled, id)
+		case wasEnabled && !isEnabled:
+			diff.NewlyDisabled = append(diff.NewlyDisabled, id)
+		}
+	}
+	slices.Sort(diff.NewlyEnabled)
+	slices.Sort(diff.NewlyDisabled)
+
+	diff.DefaultLimitsChanged = old.DefaultLimits != next.DefaultLimits
+	diff.GlobalLimitsChanged = old.GlobalLimits != next.GlobalLimits
+	return diff
+}
+
+// Watcher polls a config file for changes, re-parsing and Validate()-ing it
+// on every settled write before swapping it in as Current, and reporting
+// what changed on Changes(). A write that fails to parse or validate (e.g.
+// an editor mid-save) is simply skipped; Current keeps returning the last
+// config that succeeded.
+type Watcher struct {
+	path    string
+	current atomic.Pointer[Config]
+	changes chan ConfigDiff
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// Watch loads path, validates it, and starts polling it for further
+// changes. The returned Watcher's Current reflects the just-loaded Config
+// until a later write replaces it.
+func Watch(path string) (*Watcher, error) {
+	initial, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := initial.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watcher{
+		path:    path,
+		changes: make(chan ConfigDiff, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	w.current.Store(initial)
+
+	go w.run(ctx)
+	return w, nil
+}
+
+// Current returns the most recently loaded and validated Config.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Changes reports a ConfigDiff each time a settled file write produces a
+// Config that differs from the previous one. The channel is buffered by
+// one and never blocks Watcher's internal loop: a diff the caller hasn't
+// drained yet is dropped in favor of the newest one, since Current() always
+// has the latest state regardless.
+func (w *Watcher) Changes() <-chan ConfigDiff {
+	return w.changes
+}
+
+// Close stops the watch goroutine and waits for it to exit.
+func (w *Watcher) Close() error {
+	w.cancel()
+	<-w.done
+	return nil
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	lastMod, _ := fileModTime(w.path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mod, err := fileModTime(w.path)
+			if err != nil || mod.Equal(lastMod) {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(watchDebounce):
+			}
+
+			settled, err := fileModTime(w.path)
+			if err != nil || !settled.Equal(mod) {
+				// Still being written; pick up the final state on a later tick.
+				continue
+			}
+			lastMod = settled
+
+			w.reload()
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	next, err := Load(w.path)
+	if err != nil {
+		return
+	}
+	if err := next.Validate(); err != nil {
+		return
+	}
+
+	old := w.current.Swap(next)
+	diff := diffConfigs(old, next)
+	if diff.IsZero() {
+		return
+	}
+
+	select {
+	case w.changes <- diff:
+	default:
+		select {
+		case <-w.changes:
+		default:
+		}
+		select {
+		case w.changes <- diff:
+		default:
+		}
+	}
+}
+
+func fileModTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
@@ -2,12 +2,14 @@ package events
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events/proto"
 	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
 )
 
@@ -18,31 +20,132 @@ type Subscription struct {
 	Priority        plugin.Priority
 	Handler         Handler
 	IgnoreCancelled bool
+
+	// Timeout bounds how long Dispatch waits for this subscriber specifically,
+	// overriding WorkerPoolConfig.CancellableTimeout. Zero means "use the
+	// pool's default".
+	Timeout time.Duration
 }
 
 type EventResult struct {
-	Cancelled     bool
-	Modifications map[string]string
-	Error         string
+	Cancelled bool
+	Patch     *proto.Patch
+	Error     string
+}
+
+// WorkerPoolConfig controls how a Dispatcher spreads subscriber calls across
+// per-plugin worker goroutines instead of running them inline on the
+// caller's goroutine.
+type WorkerPoolConfig struct {
+	// Workers is the number of goroutines draining each plugin's pool.
+	// Extism plugin instances execute one call at a time (they hold a
+	// mutex for the duration of Instance.Call), so raising this above 1
+	// only helps once a plugin can itself run calls concurrently.
+	Workers int
+	// QueueSize bounds how many cancellable jobs can wait per plugin before
+	// the oldest queued job is dropped to make room for the newest one.
+	QueueSize int
+	// CancellableTimeout bounds how long Dispatch waits on a single
+	// subscriber before giving up on its vote and moving on to the next
+	// one. A context deadline shorter than this takes precedence.
+	CancellableTimeout time.Duration
+}
+
+// DefaultWorkerPoolConfig returns the settings a Dispatcher uses when none
+// are supplied: one worker per plugin, a modest queue, and a tight
+// cancellable timeout suited to per-tick events like chat and block breaks.
+func DefaultWorkerPoolConfig() WorkerPoolConfig {
+	return WorkerPoolConfig{
+		Workers:            1,
+		QueueSize:          32,
+		CancellableTimeout: 50 * time.Millisecond,
+	}
 }
 
 type Dispatcher struct {
 	mu            sync.RWMutex
 	subscriptions map[plugin.EventType][]Subscription
 	logger        *zap.Logger
+	poolConfig    WorkerPoolConfig
+	pools         map[string]*pluginPool
 	eventCount    map[plugin.EventType]uint64
 	cancelCount   map[plugin.EventType]uint64
 	dispatchTimes map[plugin.EventType]time.Duration
+	panicCount    map[string]uint64
+
+	// mergeFunc resolves a field staged by more than one handler in the same
+	// Dispatch call. Nil means proto.Patch's own default, LastWriterWins.
+	mergeFunc proto.MergeFunc
 }
 
 func NewDispatcher(logger *zap.Logger) *Dispatcher {
+	return NewDispatcherWithConfig(logger, DefaultWorkerPoolConfig())
+}
+
+// NewDispatcherWithConfig is like NewDispatcher but lets the caller size the
+// per-plugin worker pools, typically from config.PerformanceConfig.
+func NewDispatcherWithConfig(logger *zap.Logger, cfg WorkerPoolConfig) *Dispatcher {
 	return &Dispatcher{
 		subscriptions: make(map[plugin.EventType][]Subscription),
 		logger:        logger,
+		poolConfig:    cfg,
+		pools:         make(map[string]*pluginPool),
 		eventCount:    make(map[plugin.EventType]uint64),
 		cancelCount:   make(map[plugin.EventType]uint64),
 		dispatchTimes: make(map[plugin.EventType]time.Duration),
+		panicCount:    make(map[string]uint64),
+	}
+}
+
+// SetMergeFunc chooses the conflict policy Dispatch uses when two
+// subscribers, in the same or different priority bands, stage the same
+// Patch field in one dispatch. Passing nil restores the default, proto.LastWriterWins.
+func (d *Dispatcher) SetMergeFunc(fn proto.MergeFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.mergeFunc = fn
+}
+
+// PanicCount returns how many times pluginID's handlers have panicked across
+// every Dispatch call so far. A crash supervisor can poll this alongside its
+// own health checks to treat a panicking handler the same as any other
+// repeated failure.
+func (d *Dispatcher) PanicCount(pluginID string) uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.panicCount[pluginID]
+}
+
+func (d *Dispatcher) recordPanic(pluginID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.panicCount[pluginID]++
+}
+
+// poolFor returns the worker pool for pluginID, creating it on first use.
+func (d *Dispatcher) poolFor(pluginID string) *pluginPool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pool, ok := d.pools[pluginID]
+	if !ok {
+		pool = newPluginPool(d.poolConfig)
+		d.pools[pluginID] = pool
+	}
+	return pool
+}
+
+// PoolDepths reports how much queued or pending work each plugin's pool is
+// carrying, for diagnostics.
+func (d *Dispatcher) PoolDepths() map[string]int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	depths := make(map[string]int, len(d.pools))
+	for id, pool := range d.pools {
+		depths[id] = pool.depth()
 	}
+	return depths
 }
 
 func (d *Dispatcher) Subscribe(event plugin.EventType, sub Subscription) {
@@ -75,21 +178,59 @@ func (d *Dispatcher) UnsubscribeEvent(pluginID string, event plugin.EventType) {
 	})
 }
 
+// RemovePlugin unsubscribes pluginID from every event and closes its worker
+// pool, stopping the goroutines poolFor started for it. Call this instead of
+// Unsubscribe when pluginID is being unloaded rather than just resetting its
+// subscriptions (e.g. before a rebuild that will call Subscribe again) -
+// Unsubscribe alone left the pool and its goroutines running forever, which
+// leaked one pool per unload on every dev-mode hot-reload cycle.
+func (d *Dispatcher) RemovePlugin(pluginID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for event, subs := range d.subscriptions {
+		d.subscriptions[event] = slices.DeleteFunc(subs, func(s Subscription) bool {
+			return s.PluginID == pluginID
+		})
+	}
+
+	if pool, ok := d.pools[pluginID]; ok {
+		pool.close()
+		delete(d.pools, pluginID)
+	}
+}
+
 type DispatchResult struct {
-	Cancelled     bool
-	Modifications map[string]string
-	Handlers      int
-	Duration      time.Duration
-	Errors        []error
+	Cancelled bool
+	Patch     *proto.Patch
+	Handlers  int
+	Duration  time.Duration
+	Errors    []error
 }
 
 type EventData interface {
 	String() string
 }
 
+// Dispatch runs every subscriber for event on its plugin's worker pool and
+// waits, up to d.poolConfig.CancellableTimeout (or a subscriber's own
+// Timeout) per subscriber, for its vote. Subscribers sharing the same
+// Priority run concurrently with one another; different priority bands still
+// run strictly in ascending order, and Cancelled+IgnoreCancelled are checked
+// between bands the same way they used to be checked between individual
+// subscribers - a cancelled event skips every later subscriber unless it set
+// IgnoreCancelled. A subscriber's handler panicking is recovered, recorded
+// as both a DispatchResult error and against PanicCount(pluginID), and
+// treated like any other failed vote. It never runs a subscriber's handler
+// directly on the caller's goroutine, so a slow or hung plugin holds up the
+// caller only until its subscriber's timeout, never indefinitely. Use this
+// for events a handler may
+// cancel or patch; for high-frequency events nothing waits on (PlayerMove,
+// PlayerJump), use DispatchFireAndForget instead.
 func (d *Dispatcher) Dispatch(ctx context.Context, event plugin.EventType, data EventData) (*DispatchResult, error) {
 	d.mu.RLock()
 	subs := slices.Clone(d.subscriptions[event])
+	mergeFunc := d.mergeFunc
 	d.mu.RUnlock()
 
 	if len(subs) == 0 {
@@ -98,59 +239,199 @@ func (d *Dispatcher) Dispatch(ctx context.Context, event plugin.EventType, data
 
 	start := time.Now()
 	serialized := []byte(data.String())
-	result := &DispatchResult{Modifications: make(map[string]string)}
-
-	for _, sub := range subs {
-		if result.Cancelled && sub.IgnoreCancelled {
-			continue
+	result := &DispatchResult{Patch: proto.NewPatch()}
+
+	for _, band := range bandByPriority(subs) {
+		runnable := band
+		if result.Cancelled {
+			runnable = slices.DeleteFunc(slices.Clone(band), func(s Subscription) bool {
+				return !s.IgnoreCancelled
+			})
 		}
-
-		handlerResult, err := sub.Handler(ctx, serialized)
-		if err != nil {
-			result.Errors = append(result.Errors, err)
-			d.logger.Error("event handler error",
-				zap.String("plugin", sub.PluginID),
-				zap.String("event", string(event)),
-				zap.Error(err),
-			)
+		if len(runnable) == 0 {
 			continue
 		}
 
-		result.Handlers++
-		if handlerResult != nil {
-			if handlerResult.Cancelled {
-				result.Cancelled = true
+		outcomes := make([]subOutcome, len(runnable))
+		var wg sync.WaitGroup
+		wg.Add(len(runnable))
+		for i, sub := range runnable {
+			go func(i int, sub Subscription) {
+				defer wg.Done()
+				outcomes[i] = d.runCancellable(ctx, event, serialized, sub)
+			}(i, sub)
+		}
+		wg.Wait()
+
+		for i, out := range outcomes {
+			sub := runnable[i]
+			if !out.ok {
+				d.logger.Warn("plugin missed dispatch deadline",
+					zap.String("plugin", sub.PluginID),
+					zap.String("event", string(event)),
+				)
+				continue
+			}
+			if out.err != nil {
+				result.Errors = append(result.Errors, out.err)
+				d.logger.Error("event handler error",
+					zap.String("plugin", sub.PluginID),
+					zap.String("event", string(event)),
+					zap.Error(out.err),
+				)
+				continue
 			}
-			for k, v := range handlerResult.Modifications {
-				result.Modifications[k] = v
+
+			result.Handlers++
+			if out.result != nil {
+				if out.result.Cancelled {
+					result.Cancelled = true
+				}
+				if err := result.Patch.MergeWith(out.result.Patch, mergeFunc); err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("plugin %s: %w", sub.PluginID, err))
+				}
 			}
 		}
 	}
 
 	result.Duration = time.Since(start)
+	d.recordMetrics(event, result.Cancelled, result.Duration)
 
-	d.mu.Lock()
-	d.eventCount[event]++
-	if result.Cancelled {
-		d.cancelCount[event]++
+	return result, nil
+}
+
+// bandByPriority groups subs (already sorted ascending by Priority via
+// Subscribe) into consecutive runs sharing the same Priority, so Dispatch can
+// run each band's subscribers concurrently while still moving between bands
+// in order.
+func bandByPriority(subs []Subscription) [][]Subscription {
+	bands := make([][]Subscription, 0, len(subs))
+	for i := 0; i < len(subs); {
+		j := i + 1
+		for j < len(subs) && subs[j].Priority == subs[i].Priority {
+			j++
+		}
+		bands = append(bands, subs[i:j])
+		i = j
+	}
+	return bands
+}
+
+type subOutcome struct {
+	result *EventResult
+	err    error
+	ok     bool
+}
+
+// runCancellable submits sub's handler call to its plugin's pool and waits
+// for a response, bounded by sub.Timeout (falling back to
+// d.poolConfig.CancellableTimeout) or ctx's own deadline, whichever is
+// sooner. ok is false if the job was dropped for a full queue or the
+// deadline passed before a response arrived; the subscriber is treated as
+// having no vote.
+func (d *Dispatcher) runCancellable(ctx context.Context, event plugin.EventType, data []byte, sub Subscription) subOutcome {
+	pool := d.poolFor(sub.PluginID)
+	resultCh := make(chan subOutcome, 1)
+
+	timeout := sub.Timeout
+	if timeout <= 0 {
+		timeout = d.poolConfig.CancellableTimeout
+	}
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
 	}
-	d.dispatchTimes[event] += result.Duration
-	d.mu.Unlock()
 
-	return result, nil
+	accepted := pool.submitQueued(job{
+		eventType: event,
+		run: func() {
+			res, err := d.callWithRecover(ctx, timeout, sub, data)
+			resultCh <- subOutcome{result: res, err: err}
+		},
+	})
+	if !accepted {
+		return subOutcome{}
+	}
+
+	select {
+	case out := <-resultCh:
+		out.ok = true
+		return out
+	case <-time.After(timeout):
+		return subOutcome{}
+	case <-ctx.Done():
+		return subOutcome{}
+	}
 }
 
-func (d *Dispatcher) DispatchAsync(ctx context.Context, event plugin.EventType, data EventData) <-chan *DispatchResult {
-	ch := make(chan *DispatchResult, 1)
-	go func() {
-		result, err := d.Dispatch(ctx, event, data)
-		if err != nil {
-			result = &DispatchResult{Errors: []error{err}}
+// callWithRecover invokes sub.Handler under a context bounded by timeout,
+// converting a panic into an error and incrementing d.panicCount[sub.PluginID]
+// instead of letting it cross the goroutine boundary and take the whole
+// dispatch down - one misbehaving plugin shouldn't be able to crash the
+// server's event loop.
+func (d *Dispatcher) callWithRecover(ctx context.Context, timeout time.Duration, sub Subscription, data []byte) (result *EventResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.recordPanic(sub.PluginID)
+			err = fmt.Errorf("handler panicked: %v", r)
 		}
-		ch <- result
-		close(ch)
 	}()
-	return ch
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return sub.Handler(callCtx, data)
+}
+
+// DispatchFireAndForget queues event for every subscriber without waiting
+// for a result. Each subscriber runs on its plugin's worker pool; if that
+// plugin is still busy with a previous invocation of the same event type,
+// the pending one is replaced rather than queued, so a flood of
+// high-frequency events collapses to "handle the latest" instead of
+// growing an unbounded backlog.
+func (d *Dispatcher) DispatchFireAndForget(ctx context.Context, event plugin.EventType, data EventData) {
+	d.mu.RLock()
+	subs := slices.Clone(d.subscriptions[event])
+	d.mu.RUnlock()
+
+	if len(subs) == 0 {
+		return
+	}
+
+	serialized := []byte(data.String())
+	for _, sub := range subs {
+		sub := sub
+		pool := d.poolFor(sub.PluginID)
+		timeout := sub.Timeout
+		if timeout <= 0 {
+			timeout = d.poolConfig.CancellableTimeout
+		}
+		pool.submitCoalesced(event, func() {
+			start := time.Now()
+			result, err := d.callWithRecover(ctx, timeout, sub, serialized)
+			if err != nil {
+				d.logger.Error("event handler error",
+					zap.String("plugin", sub.PluginID),
+					zap.String("event", string(event)),
+					zap.Error(err),
+				)
+				return
+			}
+			d.recordMetrics(event, result != nil && result.Cancelled, time.Since(start))
+		})
+	}
+}
+
+func (d *Dispatcher) recordMetrics(event plugin.EventType, cancelled bool, duration time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.eventCount[event]++
+	if cancelled {
+		d.cancelCount[event]++
+	}
+	d.dispatchTimes[event] += duration
 }
 
 func (d *Dispatcher) HasSubscribers(event plugin.EventType) bool {
@@ -0,0 +1,250 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/events/proto"
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+type stringEventData string
+
+func (d stringEventData) String() string { return string(d) }
+
+func newTestDispatcher() *Dispatcher {
+	return NewDispatcherWithConfig(zap.NewNop(), WorkerPoolConfig{
+		Workers:            1,
+		QueueSize:          8,
+		CancellableTimeout: 200 * time.Millisecond,
+	})
+}
+
+func TestDispatchSamePriorityRunsConcurrently(t *testing.T) {
+	d := newTestDispatcher()
+	const n = 4
+	release := make(chan struct{})
+	var inFlight int32
+	var maxInFlight int32
+
+	for i := 0; i < n; i++ {
+		i := i
+		d.Subscribe("tick", Subscription{
+			PluginID: "plugin" + string(rune('a'+i)),
+			Priority: plugin.PriorityNormal,
+			Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+				cur := atomic.AddInt32(&inFlight, 1)
+				for {
+					old := atomic.LoadInt32(&maxInFlight)
+					if cur <= old || atomic.CompareAndSwapInt32(&maxInFlight, old, cur) {
+						break
+					}
+				}
+				<-release
+				atomic.AddInt32(&inFlight, -1)
+				return &EventResult{}, nil
+			},
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = d.Dispatch(context.Background(), "tick", stringEventData("x"))
+		close(done)
+	}()
+
+	// Give every same-priority subscriber a chance to start before
+	// releasing them; if they ran sequentially, only one would ever be
+	// in-flight at once.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-done
+
+	if maxInFlight < 2 {
+		t.Errorf("max concurrent handlers in the same priority band = %d, want >= 2", maxInFlight)
+	}
+}
+
+func TestDispatchBandsRunInOrder(t *testing.T) {
+	d := newTestDispatcher()
+	var mu sync.Mutex
+	var order []string
+
+	record := func(label string) Handler {
+		return func(ctx context.Context, data []byte) (*EventResult, error) {
+			mu.Lock()
+			order = append(order, label)
+			mu.Unlock()
+			return &EventResult{}, nil
+		}
+	}
+
+	d.Subscribe("tick", Subscription{PluginID: "low", Priority: plugin.PriorityLow, Handler: record("low")})
+	d.Subscribe("tick", Subscription{PluginID: "high", Priority: plugin.PriorityHigh, Handler: record("high")})
+	d.Subscribe("tick", Subscription{PluginID: "normal", Priority: plugin.PriorityNormal, Handler: record("normal")})
+
+	if _, err := d.Dispatch(context.Background(), "tick", stringEventData("x")); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+
+	want := []string{"low", "normal", "high"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("order = %v, want %v", order, want)
+			break
+		}
+	}
+}
+
+func TestDispatchCancelledSkipsLaterBandsUnlessIgnored(t *testing.T) {
+	d := newTestDispatcher()
+	var ranHonors, ranIgnores bool
+
+	d.Subscribe("tick", Subscription{
+		PluginID: "canceller",
+		Priority: plugin.PriorityLow,
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			return &EventResult{Cancelled: true}, nil
+		},
+	})
+	d.Subscribe("tick", Subscription{
+		PluginID: "honors-cancel",
+		Priority: plugin.PriorityHigh,
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			ranHonors = true
+			return &EventResult{}, nil
+		},
+	})
+	d.Subscribe("tick", Subscription{
+		PluginID:        "ignores-cancel",
+		Priority:        plugin.PriorityHigh,
+		IgnoreCancelled: true,
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			ranIgnores = true
+			return &EventResult{}, nil
+		},
+	})
+
+	result, err := d.Dispatch(context.Background(), "tick", stringEventData("x"))
+	if err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if !result.Cancelled {
+		t.Errorf("result.Cancelled = false, want true")
+	}
+	if ranHonors {
+		t.Errorf("subscriber without IgnoreCancelled ran after cancellation")
+	}
+	if !ranIgnores {
+		t.Errorf("subscriber with IgnoreCancelled = true did not run after cancellation")
+	}
+}
+
+func TestDispatchRecoversPanicAndTracksPanicCount(t *testing.T) {
+	d := newTestDispatcher()
+	d.Subscribe("tick", Subscription{
+		PluginID: "flaky",
+		Priority: plugin.PriorityNormal,
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			panic("boom")
+		},
+	})
+
+	result, err := d.Dispatch(context.Background(), "tick", stringEventData("x"))
+	if err != nil {
+		t.Fatalf("Dispatch returned an error itself: %v", err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("result.Errors = %v, want exactly one recovered panic", result.Errors)
+	}
+	if got := d.PanicCount("flaky"); got != 1 {
+		t.Errorf("PanicCount(flaky) = %d, want 1", got)
+	}
+}
+
+func TestDispatchSubscriptionTimeout(t *testing.T) {
+	d := newTestDispatcher()
+	d.Subscribe("tick", Subscription{
+		PluginID: "slow",
+		Priority: plugin.PriorityNormal,
+		Timeout:  10 * time.Millisecond,
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	result, err := d.Dispatch(context.Background(), "tick", stringEventData("x"))
+	if err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if result.Handlers != 0 {
+		t.Errorf("result.Handlers = %d, want 0 (the subscriber missed its deadline)", result.Handlers)
+	}
+}
+
+func TestDispatchUsesConfiguredMergeFunc(t *testing.T) {
+	d := newTestDispatcher()
+	d.SetMergeFunc(proto.FirstWriterWins)
+
+	d.Subscribe("tick", Subscription{
+		PluginID: "first",
+		Priority: plugin.PriorityLow,
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			p := proto.NewPatch()
+			_ = p.Set("message", "first")
+			return &EventResult{Patch: p}, nil
+		},
+	})
+	d.Subscribe("tick", Subscription{
+		PluginID: "second",
+		Priority: plugin.PriorityHigh,
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			p := proto.NewPatch()
+			_ = p.Set("message", "second")
+			return &EventResult{Patch: p}, nil
+		},
+	})
+
+	result, err := d.Dispatch(context.Background(), "tick", stringEventData("x"))
+	if err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	if got, _ := result.Patch.GetString("message"); got != "first" {
+		t.Errorf("message = %q, want \"first\" (FirstWriterWins policy)", got)
+	}
+}
+
+func TestRemovePluginUnsubscribesAndClosesPool(t *testing.T) {
+	d := newTestDispatcher()
+	d.Subscribe("tick", Subscription{
+		PluginID: "p1",
+		Handler: func(ctx context.Context, data []byte) (*EventResult, error) {
+			return &EventResult{}, nil
+		},
+	})
+	d.poolFor("p1") // force the pool into existence, as Dispatch would
+
+	d.RemovePlugin("p1")
+
+	if d.HasSubscribers("tick") {
+		t.Errorf("HasSubscribers(\"tick\") = true after RemovePlugin, want false")
+	}
+
+	d.mu.RLock()
+	_, stillTracked := d.pools["p1"]
+	d.mu.RUnlock()
+	if stillTracked {
+		t.Errorf("pools[\"p1\"] still present after RemovePlugin, want it removed")
+	}
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+// LifecycleEventType names a plugin-manager state transition, as opposed to
+// plugin.EventType which names an in-game event a plugin subscribes to via
+// Dispatcher. The two are deliberately separate: Dispatcher routes gameplay
+// events into a single plugin's handle_event export, while LifecycleBus
+// broadcasts manager-level state changes to anything interested in plugin
+// health (a CLI "plugins watch" command, metrics exporter, or the
+// supervisor's own restart bookkeeping).
+type LifecycleEventType string
+
+const (
+	LifecyclePluginDiscovered  LifecycleEventType = "plugin_discovered"
+	LifecyclePluginLoaded      LifecycleEventType = "plugin_loaded"
+	LifecyclePluginEnabling    LifecycleEventType = "plugin_enabling"
+	LifecyclePluginEnabled     LifecycleEventType = "plugin_enabled"
+	LifecyclePluginDisabling   LifecycleEventType = "plugin_disabling"
+	LifecyclePluginDisabled    LifecycleEventType = "plugin_disabled"
+	LifecyclePluginUnloaded    LifecycleEventType = "plugin_unloaded"
+	LifecyclePluginErrored     LifecycleEventType = "plugin_errored"
+	LifecyclePluginCallTimeout LifecycleEventType = "plugin_call_timeout"
+
+	// LifecyclePluginRestarting fires once per supervisor restart attempt,
+	// before the instance is rebuilt - Err is unset here since the attempt
+	// hasn't concluded yet.
+	LifecyclePluginRestarting LifecycleEventType = "plugin_restarting"
+	// LifecyclePluginRestarted fires after a restart attempt concludes, with
+	// Err set if the rebuild itself failed (as opposed to the crash that
+	// triggered the restart, reported separately via PluginErrored).
+	LifecyclePluginRestarted LifecycleEventType = "plugin_restarted"
+)
+
+// LifecycleEvent describes one state transition. Previous/Next are the zero
+// State ("") when Type doesn't represent a State-to-State move (e.g.
+// LifecyclePluginDiscovered, which fires before a plugin has any State at
+// all) or when Err makes them redundant (LifecyclePluginCallTimeout).
+type LifecycleEvent struct {
+	Type     LifecycleEventType
+	PluginID plugin.PluginID
+	Previous plugin.State
+	Next     plugin.State
+	Time     time.Time
+	Err      error
+}
+
+// LifecycleHandler receives every LifecycleEvent a subscription's
+// LifecycleFilter lets through. It's called synchronously from Emit's
+// goroutine, so a slow handler delays other subscribers - unlike
+// Dispatcher.Dispatch, which runs its Handlers on per-plugin worker pools.
+type LifecycleHandler func(LifecycleEvent)
+
+// LifecycleFilter reports whether ev should reach a subscription's handler.
+// A nil filter matches everything.
+type LifecycleFilter func(ev LifecycleEvent) bool
+
+// LifecycleSubscription identifies a Subscribe call for a later Unsubscribe.
+type LifecycleSubscription int
+
+type lifecycleSubscription struct {
+	filter  LifecycleFilter
+	handler LifecycleHandler
+}
+
+// LifecycleBus fans plugin-manager state transitions out to any number of
+// subscribers. Unlike Dispatcher it carries no per-plugin worker pools or
+// cancellation semantics: lifecycle events are informational, not votes on
+// whether an in-game action proceeds, so Emit just calls every matching
+// handler in turn.
+type LifecycleBus struct {
+	mu   sync.RWMutex
+	subs map[LifecycleSubscription]lifecycleSubscription
+	next LifecycleSubscription
+}
+
+func NewLifecycleBus() *LifecycleBus {
+	return &LifecycleBus{
+		subs: make(map[LifecycleSubscription]lifecycleSubscription),
+	}
+}
+
+// Subscribe registers handler to receive every LifecycleEvent filter
+// accepts (or every event, if filter is nil), returning a token Unsubscribe
+// later accepts.
+func (b *LifecycleBus) Subscribe(filter LifecycleFilter, handler LifecycleHandler) LifecycleSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.next++
+	sub := b.next
+	b.subs[sub] = lifecycleSubscription{filter: filter, handler: handler}
+	return sub
+}
+
+// Unsubscribe removes a subscription registered via Subscribe. Unsubscribing
+// an unknown or already-removed token is a no-op.
+func (b *LifecycleBus) Unsubscribe(sub LifecycleSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, sub)
+}
+
+// Emit notifies every matching subscriber of ev. Subscribers are snapshotted
+// under a read lock and called outside of it, so a handler that calls back
+// into Subscribe or Unsubscribe doesn't deadlock.
+func (b *LifecycleBus) Emit(ev LifecycleEvent) {
+	b.mu.RLock()
+	matching := make([]lifecycleSubscription, 0, len(b.subs))
+	for _, sub := range b.subs {
+		if sub.filter == nil || sub.filter(ev) {
+			matching = append(matching, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range matching {
+		sub.handler(ev)
+	}
+}
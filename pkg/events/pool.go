@@ -0,0 +1,149 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+// job is a single subscriber invocation queued on a pluginPool.
+type job struct {
+	eventType plugin.EventType
+	run       func()
+}
+
+// pluginPool runs queued subscriber invocations for one plugin on a fixed
+// set of goroutines, so a slow or misbehaving plugin can never stall the
+// caller's goroutine (the dragonfly server tick).
+//
+// It serves the dispatcher's two call shapes differently:
+//   - Cancellable dispatch submits to queue, a bounded channel that drops
+//     the oldest waiting job when full; the caller is already waiting with
+//     its own deadline, so a dropped job's caller just gets no vote once
+//     that deadline passes.
+//   - Fire-and-forget dispatch submits to pending, which holds at most one
+//     job per event type. A new job for an event type that already has one
+//     queued replaces it, coalescing a burst of high-frequency events
+//     (PlayerMove, PlayerJump) into "handle the latest" instead of growing
+//     an unbounded backlog.
+type pluginPool struct {
+	queue chan job
+	wake  chan struct{}
+
+	// stop is closed by close() to make run exit. A dedicated channel, rather
+	// than closing queue/wake themselves, means a submitQueued/submitCoalesced
+	// call racing a close (e.g. Dispatch fetched the pool via poolFor just
+	// before Manager.UnloadPlugin removed it) sends into an abandoned but
+	// still-open channel instead of panicking on a send to a closed one.
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu      sync.Mutex
+	pending map[plugin.EventType]func()
+}
+
+func newPluginPool(cfg WorkerPoolConfig) *pluginPool {
+	p := &pluginPool{
+		queue:   make(chan job, cfg.QueueSize),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		pending: make(map[plugin.EventType]func()),
+	}
+	workers := max(cfg.Workers, 1)
+	for i := 0; i < workers; i++ {
+		go p.run()
+	}
+	return p
+}
+
+func (p *pluginPool) run() {
+	for {
+		select {
+		case j := <-p.queue:
+			j.run()
+		case <-p.wake:
+			p.drainPending()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// drainPending runs every job waiting in pending, re-checking the map after
+// each one so a job added while this runs is still picked up even if its
+// wake signal was coalesced away.
+func (p *pluginPool) drainPending() {
+	for {
+		p.mu.Lock()
+		var event plugin.EventType
+		var fn func()
+		for e, f := range p.pending {
+			event, fn = e, f
+			break
+		}
+		if fn != nil {
+			delete(p.pending, event)
+		}
+		p.mu.Unlock()
+
+		if fn == nil {
+			return
+		}
+		fn()
+	}
+}
+
+// submitQueued enqueues j, dropping the oldest queued job if the pool is
+// already full. It reports whether j was accepted.
+func (p *pluginPool) submitQueued(j job) bool {
+	select {
+	case p.queue <- j:
+		return true
+	default:
+	}
+
+	select {
+	case <-p.queue:
+	default:
+	}
+
+	select {
+	case p.queue <- j:
+		return true
+	default:
+		return false
+	}
+}
+
+// submitCoalesced replaces any job still waiting for event with fn and
+// wakes a worker to run it.
+func (p *pluginPool) submitCoalesced(event plugin.EventType, fn func()) {
+	p.mu.Lock()
+	p.pending[event] = fn
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// depth reports how much work is currently waiting on the pool, for
+// diagnostics.
+func (p *pluginPool) depth() int {
+	p.mu.Lock()
+	pending := len(p.pending)
+	p.mu.Unlock()
+	return pending + len(p.queue)
+}
+
+// close stops every worker goroutine run started for p. It's safe to call
+// more than once and safe to race against a concurrent submitQueued/
+// submitCoalesced: queue and wake are left open, so a submission that loses
+// the race just lands in an abandoned pool instead of panicking on a send to
+// a closed channel.
+func (p *pluginPool) close() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
@@ -0,0 +1,141 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+func blockedPool(t *testing.T) (*pluginPool, chan struct{}) {
+	t.Helper()
+	block := make(chan struct{})
+	started := make(chan struct{})
+	p := newPluginPool(WorkerPoolConfig{Workers: 1, QueueSize: 1})
+	// Occupy the pool's single worker so subsequent submitQueued/
+	// submitCoalesced calls pile up instead of running immediately. Wait for
+	// the job to actually start so later assertions about queue/pending
+	// state aren't racing the worker goroutine's startup.
+	p.submitQueued(job{run: func() {
+		close(started)
+		<-block
+	}})
+	<-started
+	return p, block
+}
+
+func TestPluginPoolSubmitQueuedDropsOldestWhenFull(t *testing.T) {
+	p, block := blockedPool(t)
+
+	var mu sync.Mutex
+	var ran []int
+
+	// The worker is stuck on block, so both of these sit in the queue
+	// (capacity 1): the second submission should evict the first.
+	p.submitQueued(job{run: func() { mu.Lock(); ran = append(ran, 1); mu.Unlock() }})
+	accepted := p.submitQueued(job{run: func() { mu.Lock(); ran = append(ran, 2); mu.Unlock() }})
+	if !accepted {
+		t.Fatalf("submitQueued for job 2 was rejected, want accepted (oldest should be dropped)")
+	}
+
+	close(block)
+
+	deadline := time.After(time.Second)
+	for {
+		mu.Lock()
+		n := len(ran)
+		mu.Unlock()
+		if n >= 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("no queued job ran in time")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, v := range ran {
+		if v == 1 {
+			t.Errorf("job 1 ran, want it dropped in favor of job 2")
+		}
+	}
+}
+
+func TestPluginPoolSubmitCoalesced(t *testing.T) {
+	// The pool's only worker is stuck on block for the first part of this
+	// test, so both submitCoalesced calls below land in pending before
+	// drainPending ever runs - the second must replace the first rather
+	// than queuing alongside it.
+	p, block := blockedPool(t)
+
+	var mu sync.Mutex
+	var ran []string
+	done := make(chan struct{}, 1)
+
+	p.submitCoalesced(plugin.EventType("move"), func() {
+		mu.Lock()
+		ran = append(ran, "first")
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	p.submitCoalesced(plugin.EventType("move"), func() {
+		mu.Lock()
+		ran = append(ran, "second")
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	close(block)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("coalesced job never ran")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ran) != 1 {
+		t.Fatalf("ran = %v, want exactly one coalesced job to run", ran)
+	}
+	if ran[0] != "second" {
+		t.Errorf("coalesced job that ran was %q, want \"second\" (latest submission wins)", ran[0])
+	}
+}
+
+func TestPluginPoolDepth(t *testing.T) {
+	p, block := blockedPool(t)
+
+	p.submitQueued(job{run: func() {}})
+	if d := p.depth(); d == 0 {
+		t.Errorf("depth() = 0, want > 0 with a job queued behind the blocked worker")
+	}
+	close(block)
+}
+
+func TestPluginPoolCloseStopsWorkers(t *testing.T) {
+	p := newPluginPool(WorkerPoolConfig{Workers: 2, QueueSize: 1})
+
+	p.close()
+
+	// close only closes stop, which makes run exit; queue and wake stay open,
+	// so submitting after close must not panic or block, even though nothing
+	// will ever run it.
+	done := make(chan struct{})
+	go func() {
+		p.submitQueued(job{run: func() {}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("submitQueued after close blocked, want it to return")
+	}
+
+	// A second close must not panic (e.g. a racing Unsubscribe/RemovePlugin).
+	p.close()
+}
@@ -0,0 +1,51 @@
+package proto
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Envelope wraps an event body with the schema version and event type so a
+// guest can decode it without out-of-band knowledge of which message follows.
+type Envelope struct {
+	Version uint32          `json:"version"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Encode marshals body into a versioned Envelope for the given event type.
+func Encode(eventType string, body any) (*Envelope, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	return &Envelope{Version: SchemaVersion, Type: eventType, Payload: payload}, nil
+}
+
+// String renders the envelope as its wire form. It satisfies the
+// events.EventData interface so an Envelope can be dispatched directly.
+func (e *Envelope) String() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Decode parses wire bytes into an Envelope and, if out is non-nil, unmarshals
+// the payload into out.
+func Decode(data []byte, out any) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("unmarshal envelope: %w", err)
+	}
+	if env.Version != SchemaVersion {
+		return &env, fmt.Errorf("unsupported schema version %d", env.Version)
+	}
+	if out != nil {
+		if err := json.Unmarshal(env.Payload, out); err != nil {
+			return &env, fmt.Errorf("unmarshal payload: %w", err)
+		}
+	}
+	return &env, nil
+}
@@ -0,0 +1,215 @@
+// Package proto defines the typed wire schema for event payloads exchanged
+// between the host and WASM plugins, replacing the ad-hoc JSON maps that used
+// to flow through PlayerHandler.dispatchEvent.
+//
+// Despite the package name, these messages are hand-written Go structs
+// serialized with encoding/json (see Envelope), not generated from .proto
+// files - there is no protobuf codegen or wire-compatible binary encoding
+// here. What this package does provide is the thing that actually mattered
+// for guests: a fixed, versioned set of typed messages (SchemaVersion) in
+// place of untyped map[string]any payloads. A real protobuf/FlatBuffers
+// migration remains open; see the host ABI work tracked alongside chunk1-1.
+package proto
+
+import "github.com/EinBexiii/dragonfly-wasm/pkg/chat"
+
+// SchemaVersion is bumped whenever a breaking change is made to a message
+// defined in this package. Guests should reject envelopes with a version
+// they don't understand rather than guessing at field layout.
+const SchemaVersion uint32 = 1
+
+// Vec3 is a 3D floating point vector, used for positions and rotations.
+type Vec3 struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// BlockPos is an integer block-grid position.
+type BlockPos struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+	Z int32 `json:"z"`
+}
+
+// Player is a serializable snapshot of a connected player.
+type Player struct {
+	UUID      string  `json:"uuid"`
+	Name      string  `json:"name"`
+	XUID      string  `json:"xuid"`
+	Position  Vec3    `json:"position"`
+	Yaw       float32 `json:"yaw"`
+	Pitch     float32 `json:"pitch"`
+	WorldName string  `json:"world_name"`
+	Health    float64 `json:"health"`
+	MaxHealth float64 `json:"max_health"`
+}
+
+// Block is a serializable snapshot of a block and its position.
+type Block struct {
+	Type       string            `json:"type"`
+	Position   BlockPos          `json:"position"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// Item is a serializable snapshot of an item stack.
+type Item struct {
+	Type     string            `json:"type"`
+	Count    int32             `json:"count"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Entity is a serializable snapshot of a world entity.
+type Entity struct {
+	UUID  string  `json:"uuid,omitempty"`
+	Type  string  `json:"type"`
+	Pos   Vec3    `json:"position"`
+	Yaw   float32 `json:"yaw"`
+	Pitch float32 `json:"pitch"`
+}
+
+// PlayerChat is the body of an EventPlayerChat envelope. Message carries the
+// plain-text form for handlers that only read/patch a string, while
+// Component carries the component tree (color and formatting) parsed from
+// it; a legacy § string has no click or hover events, so those are unset.
+type PlayerChat struct {
+	Player    Player         `json:"player"`
+	Message   string         `json:"message"`
+	Component chat.Component `json:"component"`
+}
+
+// PlayerMove is the body of an EventPlayerMove envelope.
+type PlayerMove struct {
+	NewPosition Vec3    `json:"new_position"`
+	NewYaw      float32 `json:"new_yaw"`
+	NewPitch    float32 `json:"new_pitch"`
+}
+
+// PlayerTeleport is the body of an EventPlayerTeleport envelope.
+type PlayerTeleport struct {
+	To Vec3 `json:"to"`
+}
+
+// PlayerPresence is the body for simple player-state events such as jump,
+// sprint toggle and sneak toggle.
+type PlayerPresence struct {
+	Player Player `json:"player"`
+	Value  bool   `json:"value,omitempty"`
+}
+
+// PlayerDeath is the body of an EventPlayerDeath envelope.
+type PlayerDeath struct {
+	Player        Player `json:"player"`
+	DamageSource  string `json:"damage_source"`
+	KeepInventory bool   `json:"keep_inventory"`
+}
+
+// PlayerRespawn is the body of an EventPlayerRespawn envelope.
+type PlayerRespawn struct {
+	Player        Player `json:"player"`
+	SpawnPosition Vec3   `json:"spawn_position"`
+}
+
+// PlayerHurt is the body of an EventPlayerHurt envelope.
+type PlayerHurt struct {
+	Player       Player  `json:"player"`
+	Damage       float64 `json:"damage"`
+	Immune       bool    `json:"immune"`
+	DamageSource string  `json:"damage_source"`
+	Attacker     *Entity `json:"attacker,omitempty"`
+}
+
+// PlayerHeal is the body of an EventPlayerHeal envelope.
+type PlayerHeal struct {
+	Player     Player  `json:"player"`
+	Amount     float64 `json:"amount"`
+	HealSource string  `json:"heal_source"`
+}
+
+// BlockBreak is the body of an EventBlockBreak envelope.
+type BlockBreak struct {
+	Player     Player `json:"player"`
+	Block      Block  `json:"block"`
+	Drops      []Item `json:"drops"`
+	Experience int32  `json:"experience"`
+}
+
+// BlockPlace is the body of an EventBlockPlace envelope.
+type BlockPlace struct {
+	Player Player `json:"player"`
+	Block  Block  `json:"block"`
+}
+
+// ItemUse is the body of an EventItemUse envelope.
+type ItemUse struct {
+	Player Player `json:"player"`
+}
+
+// ItemUseOnBlock is the body of an EventItemUseOnBlock envelope.
+type ItemUseOnBlock struct {
+	Player        Player   `json:"player"`
+	Position      BlockPos `json:"position"`
+	Face          int32    `json:"face"`
+	ClickPosition Vec3     `json:"click_position"`
+}
+
+// ItemUseOnEntity is the body of an EventItemUseOnEntity envelope.
+type ItemUseOnEntity struct {
+	Player Player `json:"player"`
+	Target Entity `json:"target"`
+}
+
+// PacketDirection distinguishes packets arriving from the client from
+// packets about to be sent to it.
+type PacketDirection string
+
+const (
+	PacketReceive PacketDirection = "receive"
+	PacketSend    PacketDirection = "send"
+)
+
+// Packet is the body of an EventPacketReceive/EventPacketSend envelope. It
+// carries only metadata about the packet, not its raw bytes, since plugins
+// act on packet identity rather than needing to re-encode the payload
+// themselves.
+type Packet struct {
+	Direction  PacketDirection `json:"direction"`
+	PacketID   uint32          `json:"packet_id"`
+	PacketName string          `json:"packet_name"`
+}
+
+// ChunkPos is a chunk-grid coordinate within a dimension.
+type ChunkPos struct {
+	X int32 `json:"x"`
+	Z int32 `json:"z"`
+}
+
+// BlockDelta describes a single block change within a chunk-modify event.
+type BlockDelta struct {
+	SubchunkY int32             `json:"subchunk_y"`
+	Position  BlockPos          `json:"position"`
+	OldBlock  Block             `json:"old_block"`
+	NewBlock  Block             `json:"new_block"`
+	NBT       map[string]string `json:"nbt,omitempty"`
+}
+
+// ChunkLoad is the body of an EventChunkLoad envelope.
+type ChunkLoad struct {
+	Dimension string   `json:"dimension"`
+	Chunk     ChunkPos `json:"chunk"`
+}
+
+// ChunkUnload is the body of an EventChunkUnload envelope.
+type ChunkUnload struct {
+	Dimension string   `json:"dimension"`
+	Chunk     ChunkPos `json:"chunk"`
+}
+
+// ChunkModify is the body of an EventChunkModify envelope, carrying a delta
+// list instead of a full chunk snapshot so plugins can mirror or persist
+// world state without re-reading every block.
+type ChunkModify struct {
+	Dimension string       `json:"dimension"`
+	Chunk     ChunkPos     `json:"chunk"`
+	Deltas    []BlockDelta `json:"deltas"`
+}
@@ -0,0 +1,170 @@
+package proto
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Patch is a typed replacement for the old EventResult.Modifications
+// map[string]string back-channel: it carries a FieldMask of which fields a
+// handler wants to change plus the new values, so a mod like keep_inventory
+// no longer round-trips through the string "true".
+type Patch struct {
+	fields map[string]json.RawMessage
+}
+
+// NewPatch returns an empty Patch ready for Set calls.
+func NewPatch() *Patch {
+	return &Patch{fields: make(map[string]json.RawMessage)}
+}
+
+// Set stages value to be applied to field. It returns an error if value
+// cannot be marshaled.
+func (p *Patch) Set(field string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	p.fields[field] = data
+	return nil
+}
+
+// FieldMask returns the set of field paths this patch modifies.
+func (p *Patch) FieldMask() []string {
+	mask := make([]string, 0, len(p.fields))
+	for field := range p.fields {
+		mask = append(mask, field)
+	}
+	return mask
+}
+
+// Has reports whether field was staged by Set. A nil Patch has no fields.
+func (p *Patch) Has(field string) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.fields[field]
+	return ok
+}
+
+// GetString returns the staged string value for field, if any. It is safe
+// to call on a nil Patch.
+func (p *Patch) GetString(field string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	raw, ok := p.fields[field]
+	if !ok {
+		return "", false
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// GetBool returns the staged bool value for field, if any. It is safe to
+// call on a nil Patch.
+func (p *Patch) GetBool(field string) (bool, bool) {
+	if p == nil {
+		return false, false
+	}
+	raw, ok := p.fields[field]
+	if !ok {
+		return false, false
+	}
+	var v bool
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return false, false
+	}
+	return v, true
+}
+
+// Merge applies every field in other onto p, overwriting existing entries -
+// i.e. the LastWriterWins policy. It's kept alongside MergeWith since most
+// callers (a single handler's result folding into its own Patch) have
+// nothing to conflict with and don't need a policy at all.
+func (p *Patch) Merge(other *Patch) {
+	if other == nil {
+		return
+	}
+	for field, value := range other.fields {
+		p.fields[field] = value
+	}
+}
+
+// MergeFunc resolves a conflict where both p and an incoming Patch stage a
+// value for the same field. It returns the value to keep, or an error to
+// reject the merge entirely (see ErrorOnConflict).
+type MergeFunc func(field string, existing, incoming json.RawMessage) (json.RawMessage, error)
+
+// LastWriterWins is the MergeFunc equivalent of plain Merge: the incoming
+// value always replaces the existing one.
+func LastWriterWins(_ string, _, incoming json.RawMessage) (json.RawMessage, error) {
+	return incoming, nil
+}
+
+// FirstWriterWins keeps whichever value was staged first, discarding later
+// conflicting writes instead of silently overwriting them.
+func FirstWriterWins(_ string, existing, _ json.RawMessage) (json.RawMessage, error) {
+	return existing, nil
+}
+
+// ErrorOnConflict rejects a merge outright when two sources stage different
+// values for the same field, forcing the caller to decide rather than
+// picking a winner silently. Values that happen to be byte-for-byte
+// identical are not considered a conflict.
+func ErrorOnConflict(field string, existing, incoming json.RawMessage) (json.RawMessage, error) {
+	if string(existing) == string(incoming) {
+		return existing, nil
+	}
+	return nil, fmt.Errorf("conflicting modifications to field %q", field)
+}
+
+// UnionMerge concatenates existing and incoming when both are JSON arrays,
+// so two handlers appending to the same list field (e.g. tags to add) don't
+// clobber one another. For anything else - scalars, objects - there's no
+// sensible union, so it falls back to LastWriterWins.
+func UnionMerge(field string, existing, incoming json.RawMessage) (json.RawMessage, error) {
+	var existingList, incomingList []json.RawMessage
+	if err := json.Unmarshal(existing, &existingList); err != nil {
+		return LastWriterWins(field, existing, incoming)
+	}
+	if err := json.Unmarshal(incoming, &incomingList); err != nil {
+		return LastWriterWins(field, existing, incoming)
+	}
+	return json.Marshal(append(existingList, incomingList...))
+}
+
+// MergeWith applies every field in other onto p like Merge, but calls fn to
+// resolve any field staged by both p and other instead of blindly
+// overwriting it. A nil fn behaves exactly like Merge (LastWriterWins).
+func (p *Patch) MergeWith(other *Patch, fn MergeFunc) error {
+	if other == nil {
+		return nil
+	}
+	if fn == nil {
+		p.Merge(other)
+		return nil
+	}
+
+	for field, incoming := range other.fields {
+		existing, conflict := p.fields[field]
+		if !conflict {
+			p.fields[field] = incoming
+			continue
+		}
+		resolved, err := fn(field, existing, incoming)
+		if err != nil {
+			return err
+		}
+		p.fields[field] = resolved
+	}
+	return nil
+}
+
+// IsEmpty reports whether the patch has no staged fields.
+func (p *Patch) IsEmpty() bool {
+	return len(p.fields) == 0
+}
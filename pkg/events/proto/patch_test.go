@@ -0,0 +1,168 @@
+package proto
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPatchSetAndGet(t *testing.T) {
+	p := NewPatch()
+	if err := p.Set("keep_inventory", true); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := p.Set("message", "hi"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	if v, ok := p.GetBool("keep_inventory"); !ok || !v {
+		t.Errorf("GetBool(keep_inventory) = (%v, %v), want (true, true)", v, ok)
+	}
+	if v, ok := p.GetString("message"); !ok || v != "hi" {
+		t.Errorf("GetString(message) = (%q, %v), want (\"hi\", true)", v, ok)
+	}
+	if !p.Has("message") || p.Has("missing") {
+		t.Errorf("Has behaved unexpectedly")
+	}
+	if len(p.FieldMask()) != 2 {
+		t.Errorf("FieldMask() has %d entries, want 2", len(p.FieldMask()))
+	}
+}
+
+func TestPatchNilSafe(t *testing.T) {
+	var p *Patch
+	if p.Has("anything") {
+		t.Errorf("nil Patch.Has returned true")
+	}
+	if _, ok := p.GetString("anything"); ok {
+		t.Errorf("nil Patch.GetString returned ok")
+	}
+	if _, ok := p.GetBool("anything"); ok {
+		t.Errorf("nil Patch.GetBool returned ok")
+	}
+}
+
+func TestPatchMergeLastWriterWins(t *testing.T) {
+	dst := NewPatch()
+	_ = dst.Set("a", "first")
+
+	src := NewPatch()
+	_ = src.Set("a", "second")
+	_ = src.Set("b", "new")
+
+	dst.Merge(src)
+
+	if v, _ := dst.GetString("a"); v != "second" {
+		t.Errorf("a = %q, want overwritten to \"second\"", v)
+	}
+	if v, _ := dst.GetString("b"); v != "new" {
+		t.Errorf("b = %q, want \"new\"", v)
+	}
+}
+
+func TestPatchMergeWithPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      MergeFunc
+		want    string
+		wantErr bool
+	}{
+		{"nil falls back to LastWriterWins", nil, "second", false},
+		{"LastWriterWins", LastWriterWins, "second", false},
+		{"FirstWriterWins", FirstWriterWins, "first", false},
+		{"ErrorOnConflict", ErrorOnConflict, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := NewPatch()
+			_ = dst.Set("a", "first")
+			src := NewPatch()
+			_ = src.Set("a", "second")
+
+			err := dst.MergeWith(src, tt.fn)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("MergeWith: expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeWith: %v", err)
+			}
+			if v, _ := dst.GetString("a"); v != tt.want {
+				t.Errorf("a = %q, want %q", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchMergeWithNoConflict(t *testing.T) {
+	dst := NewPatch()
+	_ = dst.Set("a", "first")
+	src := NewPatch()
+	_ = src.Set("b", "second")
+
+	if err := dst.MergeWith(src, ErrorOnConflict); err != nil {
+		t.Fatalf("MergeWith: %v", err)
+	}
+	if v, _ := dst.GetString("b"); v != "second" {
+		t.Errorf("b = %q, want \"second\" (non-conflicting fields always copy)", v)
+	}
+}
+
+func TestUnionMerge(t *testing.T) {
+	dst := NewPatch()
+	_ = dst.Set("tags", []string{"a", "b"})
+	src := NewPatch()
+	_ = src.Set("tags", []string{"c"})
+
+	if err := dst.MergeWith(src, UnionMerge); err != nil {
+		t.Fatalf("MergeWith: %v", err)
+	}
+
+	data, ok := dst.fields["tags"]
+	if !ok {
+		t.Fatalf("tags field missing after union merge")
+	}
+	var tags []string
+	if err := json.Unmarshal(data, &tags); err != nil {
+		t.Fatalf("unmarshal merged tags: %v", err)
+	}
+	if len(tags) != 3 || tags[0] != "a" || tags[1] != "b" || tags[2] != "c" {
+		t.Errorf("tags = %v, want [a b c]", tags)
+	}
+}
+
+func TestUnionMergeFallsBackOnScalars(t *testing.T) {
+	dst := NewPatch()
+	_ = dst.Set("count", 1)
+	src := NewPatch()
+	_ = src.Set("count", 2)
+
+	if err := dst.MergeWith(src, UnionMerge); err != nil {
+		t.Fatalf("MergeWith: %v", err)
+	}
+
+	data, ok := dst.fields["count"]
+	if !ok {
+		t.Fatalf("count field missing")
+	}
+	var got int
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal merged count: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("count = %d, want 2 (last-writer-wins fallback for non-array values)", got)
+	}
+}
+
+func TestPatchIsEmpty(t *testing.T) {
+	p := NewPatch()
+	if !p.IsEmpty() {
+		t.Errorf("new Patch should be empty")
+	}
+	_ = p.Set("a", 1)
+	if p.IsEmpty() {
+		t.Errorf("Patch with a staged field should not be empty")
+	}
+}
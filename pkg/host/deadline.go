@@ -0,0 +1,62 @@
+package host
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCallTimeout bounds a host call when FunctionProvider is built with
+// NewFunctionProvider instead of NewFunctionProviderWithTimeout.
+const DefaultCallTimeout = 5 * time.Second
+
+// CallStatus distinguishes why a host call didn't return a normal result, so
+// a guest can back off differently for a slow host (CallTimeout) than for a
+// call that simply failed (CallFailed). CallOK and CallFailed keep the
+// pre-existing true/false wire values so older guests that only check "was
+// it zero" still work; CallTimeout is a new, distinct nonzero-but-not-one
+// value a guest must check for explicitly.
+type CallStatus int32
+
+const (
+	CallFailed    CallStatus = 0
+	CallOK        CallStatus = 1
+	CallTimeout   CallStatus = -1
+	CallCancelled CallStatus = -2
+)
+
+// statusUint64 zero-extends status's 32-bit representation into the uint64
+// stack slot extism host functions communicate through, so a negative
+// CallStatus round-trips as the same i32 bit pattern on the guest side.
+func statusUint64(status CallStatus) uint64 {
+	return uint64(uint32(int32(status)))
+}
+
+// runWithTimeout runs fn on its own goroutine and waits for it, bounded by
+// whichever comes first: fn returning, or ctx's own deadline or
+// p.defaultTimeout (whichever is sooner). fn's error is only meaningful when
+// CallOK is returned; the caller maps a non-OK status to the guest-facing
+// error code instead of inspecting err.
+//
+// This package previously also tracked a per-plugin cancel channel here
+// (CancelPlugin/pluginDeadline) so an external caller could abort every
+// in-flight host call for one plugin. That duplicated
+// internal/manager/deadline.go, which is the deadline mechanism actually
+// wired into the live plugin-loading path; FunctionProvider has no such
+// caller, so the duplicate was dropped rather than kept in sync.
+func (p *FunctionProvider) runWithTimeout(ctx context.Context, fn func(ctx context.Context) error) (CallStatus, error) {
+	callCtx, cancel := context.WithTimeout(ctx, p.defaultTimeout)
+	defer cancel()
+
+	resultCh := make(chan error, 1)
+	go func() { resultCh <- fn(callCtx) }()
+
+	select {
+	case err := <-resultCh:
+		if err != nil {
+			return CallFailed, err
+		}
+		return CallOK, nil
+	case <-callCtx.Done():
+		return CallTimeout, callCtx.Err()
+	}
+}
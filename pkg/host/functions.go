@@ -1,80 +1,132 @@
+// Package host implements a standalone ServerAPI/FunctionProvider host ABI,
+// built alongside internal/manager's own createHostFunctions rather than as
+// a replacement for it. internal/manager is the live path the rest of this
+// module extends (Config, the event Dispatcher, LoadedPlugin, the state
+// store); FunctionProvider here - and internal/runtime's Pool/Instance,
+// which embeds plugins through it - are not constructed from that path and
+// have no callers outside their own packages and tests.
+//
+// Where internal/manager has grown a real equivalent of a feature here
+// (storage: see hostfuncs.go's host_storage_get/set/delete), that feature
+// has been removed from this package instead of chased into parity - see
+// git history for fnStorageGet/Set/Delete and StorageBackend; for the
+// dynamic subscription ABI that used to live in events.go; for the bulk
+// region ops that used to live in region.go; for the binary wire codec
+// that used to live in codec.go/binary.go; and for the bidirectional
+// stream ABI (host_stream_open/read/write/close, StreamManager) that used
+// to live in stream.go. None of them had a caller outside this package
+// and internal/runtime, which nothing outside those two packages
+// constructs. FunctionProvider's host function surface is now just
+// player/block/item/task access plus logging and ABI version negotiation
+// - the same calls internal/manager's createHostFunctions implements
+// independently for the live path.
 package host
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
+	"time"
 
 	extism "github.com/extism/go-sdk"
 	"go.uber.org/zap"
 )
 
 const (
-	fnLog              = "host_log"
-	fnGetPlayer        = "host_get_player"
-	fnGetOnlinePlayers = "host_get_online_players"
-	fnSendMessage      = "host_send_message"
-	fnBroadcast        = "host_broadcast"
-	fnKickPlayer       = "host_kick_player"
-	fnTeleportPlayer   = "host_teleport_player"
-	fnSetPlayerHealth  = "host_set_player_health"
+	fnABIVersion        = "host_abi_version"
+	fnLog               = "host_log"
+	fnGetPlayer         = "host_get_player"
+	fnGetOnlinePlayers  = "host_get_online_players"
+	fnSendMessage       = "host_send_message"
+	fnBroadcast         = "host_broadcast"
+	fnKickPlayer        = "host_kick_player"
+	fnTeleportPlayer    = "host_teleport_player"
+	fnSetPlayerHealth   = "host_set_player_health"
 	fnSetPlayerGamemode = "host_set_player_gamemode"
-	fnGiveItem         = "host_give_item"
-	fnGetBlock         = "host_get_block"
-	fnSetBlock         = "host_set_block"
-	fnStorageGet       = "host_storage_get"
-	fnStorageSet       = "host_storage_set"
-	fnStorageDelete    = "host_storage_delete"
-	fnScheduleTask     = "host_schedule_task"
-	fnCancelTask       = "host_cancel_task"
+	fnGiveItem          = "host_give_item"
+	fnGetBlock          = "host_get_block"
+	fnSetBlock          = "host_set_block"
+	fnScheduleTask      = "host_schedule_task"
+	fnCancelTask        = "host_cancel_task"
 )
 
+// ABIVersion is the wire schema version served by this package's host
+// functions. It is independent of a plugin's Manifest.Version: bump it only
+// when a request/response struct in serialization.go changes shape in a way
+// that breaks older guests. Guests call host_abi_version before anything
+// else and refuse to run against a host whose major version they don't
+// recognize; the manager refuses to load a plugin whose
+// Manifest.APIVersion.Major doesn't match for the same reason (see
+// plugin.ABICompatible).
+const ABIVersion uint32 = 1
+
+// ServerAPI is the set of calls a host function may make on behalf of a
+// guest. Every method takes a context so a slow implementation (e.g. a
+// world transaction contending with the main tick) can be abandoned by
+// runWithTimeout instead of stalling the calling WASM instance forever.
 type ServerAPI interface {
-	GetPlayer(uuid string) (PlayerInfo, bool)
-	GetPlayerByName(name string) (PlayerInfo, bool)
-	GetOnlinePlayers() []PlayerInfo
-	SendMessage(playerUUID, message string) error
-	BroadcastMessage(message string)
-	KickPlayer(uuid, reason string) error
-	TeleportPlayer(uuid string, x, y, z float64, world string) error
-	SetPlayerHealth(uuid string, health float32) error
-	SetPlayerGameMode(uuid string, gameMode int32) error
-	GiveItem(uuid, itemType string, count int32, metadata map[string]string) error
-	GetBlock(world string, x, y, z int32) (BlockInfo, error)
-	SetBlock(world string, x, y, z int32, blockType string, properties map[string]string) error
-	StorageGet(pluginID, key string) ([]byte, bool)
-	StorageSet(pluginID, key string, value []byte) error
-	StorageDelete(pluginID, key string) error
-	ScheduleTask(pluginID, taskID string, delayMs int64, data []byte) error
-	CancelTask(pluginID, taskID string) error
+	GetPlayer(ctx context.Context, uuid string) (PlayerInfo, bool)
+	GetPlayerByName(ctx context.Context, name string) (PlayerInfo, bool)
+	GetOnlinePlayers(ctx context.Context) []PlayerInfo
+	SendMessage(ctx context.Context, playerUUID, message string) error
+	BroadcastMessage(ctx context.Context, message string)
+	KickPlayer(ctx context.Context, uuid, reason string) error
+	TeleportPlayer(ctx context.Context, uuid string, x, y, z float64, world string) error
+	SetPlayerHealth(ctx context.Context, uuid string, health float32) error
+	SetPlayerGameMode(ctx context.Context, uuid string, gameMode int32) error
+	GiveItem(ctx context.Context, uuid, itemType string, count int32, metadata map[string]string) error
+	GetBlock(ctx context.Context, world string, x, y, z int32) (BlockInfo, error)
+	SetBlock(ctx context.Context, world string, x, y, z int32, blockType string, properties map[string]string) error
+	ScheduleTask(ctx context.Context, pluginID, taskID string, delayMs int64, data []byte) error
+	CancelTask(ctx context.Context, pluginID, taskID string) error
 }
 
+// PlayerInfo and BlockInfo are the canonical wire shapes for the snapshots
+// this package's host functions hand back to guests. Field names are fixed
+// by ABIVersion; changing one is a breaking change.
 type PlayerInfo struct {
-	UUID      string
-	Name      string
-	XUID      string
-	X, Y, Z   float64
-	Yaw       float32
-	Pitch     float32
-	WorldName string
-	GameMode  int32
-	Health    float32
-	MaxHealth float32
+	UUID      string  `json:"uuid"`
+	Name      string  `json:"name"`
+	XUID      string  `json:"xuid"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Z         float64 `json:"z"`
+	Yaw       float32 `json:"yaw"`
+	Pitch     float32 `json:"pitch"`
+	WorldName string  `json:"world_name"`
+	GameMode  int32   `json:"game_mode"`
+	Health    float32 `json:"health"`
+	MaxHealth float32 `json:"max_health"`
 }
 
 type BlockInfo struct {
-	BlockType  string
-	X, Y, Z    int32
-	Properties map[string]string
+	BlockType  string            `json:"block_type"`
+	X          int32             `json:"x"`
+	Y          int32             `json:"y"`
+	Z          int32             `json:"z"`
+	Properties map[string]string `json:"properties"`
 }
 
 type FunctionProvider struct {
-	mu     sync.RWMutex
-	api    ServerAPI
-	logger *zap.Logger
+	mu             sync.Mutex
+	api            ServerAPI
+	logger         *zap.Logger
+	defaultTimeout time.Duration
 }
 
 func NewFunctionProvider(api ServerAPI, logger *zap.Logger) *FunctionProvider {
-	return &FunctionProvider{api: api, logger: logger}
+	return NewFunctionProviderWithTimeout(api, logger, DefaultCallTimeout)
+}
+
+// NewFunctionProviderWithTimeout is like NewFunctionProvider but lets the
+// caller size the per-call deadline every host function is bound by, absent
+// a shorter deadline already on the calling context.
+func NewFunctionProviderWithTimeout(api ServerAPI, logger *zap.Logger, timeout time.Duration) *FunctionProvider {
+	return &FunctionProvider{
+		api:            api,
+		logger:         logger,
+		defaultTimeout: timeout,
+	}
 }
 
 type PluginContext struct {
@@ -85,6 +137,7 @@ type PluginContext struct {
 func (p *FunctionProvider) CreateHostFunctions(pluginID string) []extism.HostFunction {
 	ctx := &PluginContext{PluginID: pluginID, Provider: p}
 	return []extism.HostFunction{
+		p.createABIVersionFunction(ctx),
 		p.createLogFunction(ctx),
 		p.createGetPlayerFunction(ctx),
 		p.createGetOnlinePlayersFunction(ctx),
@@ -97,14 +150,25 @@ func (p *FunctionProvider) CreateHostFunctions(pluginID string) []extism.HostFun
 		p.createGiveItemFunction(ctx),
 		p.createGetBlockFunction(ctx),
 		p.createSetBlockFunction(ctx),
-		p.createStorageGetFunction(ctx),
-		p.createStorageSetFunction(ctx),
-		p.createStorageDeleteFunction(ctx),
 		p.createScheduleTaskFunction(ctx),
 		p.createCancelTaskFunction(ctx),
 	}
 }
 
+// createABIVersionFunction exposes ABIVersion to guests so they can
+// negotiate compatibility before calling anything else. ctx is unused but
+// kept so every create*Function has the same shape.
+func (p *FunctionProvider) createABIVersionFunction(ctx *PluginContext) extism.HostFunction {
+	return extism.NewHostFunctionWithStack(
+		fnABIVersion,
+		func(_ context.Context, _ *extism.CurrentPlugin, stack []uint64) {
+			stack[0] = uint64(ABIVersion)
+		},
+		[]extism.ValueType{},
+		[]extism.ValueType{extism.ValueTypeI32},
+	)
+}
+
 func (p *FunctionProvider) createLogFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnLog,
@@ -131,9 +195,19 @@ func (p *FunctionProvider) createLogFunction(ctx *PluginContext) extism.HostFunc
 func (p *FunctionProvider) createGetPlayerFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnGetPlayer,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			uuid, _ := plugin.ReadString(stack[0])
-			playerInfo, found := p.api.GetPlayer(uuid)
+
+			var playerInfo PlayerInfo
+			var found bool
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				playerInfo, found = p.api.GetPlayer(callCtx, uuid)
+				return nil
+			})
+			if status != CallOK {
+				stack[0] = writeStatusError(plugin, status)
+				return
+			}
 			if !found {
 				stack[0] = 0
 				return
@@ -149,8 +223,17 @@ func (p *FunctionProvider) createGetPlayerFunction(ctx *PluginContext) extism.Ho
 func (p *FunctionProvider) createGetOnlinePlayersFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnGetOnlinePlayers,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
-			offset, _ := plugin.WriteBytes(serializePlayerInfoList(p.api.GetOnlinePlayers()))
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+			var players []PlayerInfo
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				players = p.api.GetOnlinePlayers(callCtx)
+				return nil
+			})
+			if status != CallOK {
+				stack[0] = writeStatusError(plugin, status)
+				return
+			}
+			offset, _ := plugin.WriteBytes(serializePlayerInfoList(players))
 			stack[0] = offset
 		},
 		[]extism.ValueType{},
@@ -161,10 +244,13 @@ func (p *FunctionProvider) createGetOnlinePlayersFunction(ctx *PluginContext) ex
 func (p *FunctionProvider) createSendMessageFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnSendMessage,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			uuid, _ := plugin.ReadString(stack[0])
 			message, _ := plugin.ReadString(stack[1])
-			stack[0] = boolToUint64(p.api.SendMessage(uuid, message) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.SendMessage(callCtx, uuid, message)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR, extism.ValueTypePTR},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -174,9 +260,12 @@ func (p *FunctionProvider) createSendMessageFunction(ctx *PluginContext) extism.
 func (p *FunctionProvider) createBroadcastFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnBroadcast,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			message, _ := plugin.ReadString(stack[0])
-			p.api.BroadcastMessage(message)
+			_, _ = p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				p.api.BroadcastMessage(callCtx, message)
+				return nil
+			})
 		},
 		[]extism.ValueType{extism.ValueTypePTR},
 		[]extism.ValueType{},
@@ -186,10 +275,13 @@ func (p *FunctionProvider) createBroadcastFunction(ctx *PluginContext) extism.Ho
 func (p *FunctionProvider) createKickPlayerFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnKickPlayer,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			uuid, _ := plugin.ReadString(stack[0])
 			reason, _ := plugin.ReadString(stack[1])
-			stack[0] = boolToUint64(p.api.KickPlayer(uuid, reason) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.KickPlayer(callCtx, uuid, reason)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR, extism.ValueTypePTR},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -199,10 +291,13 @@ func (p *FunctionProvider) createKickPlayerFunction(ctx *PluginContext) extism.H
 func (p *FunctionProvider) createTeleportPlayerFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnTeleportPlayer,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			data, _ := plugin.ReadBytes(stack[0])
 			req := deserializeTeleportRequest(data)
-			stack[0] = boolToUint64(p.api.TeleportPlayer(req.UUID, req.X, req.Y, req.Z, req.World) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.TeleportPlayer(callCtx, req.UUID, req.X, req.Y, req.Z, req.World)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -212,10 +307,13 @@ func (p *FunctionProvider) createTeleportPlayerFunction(ctx *PluginContext) exti
 func (p *FunctionProvider) createSetPlayerHealthFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnSetPlayerHealth,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			uuid, _ := plugin.ReadString(stack[0])
 			health := float32(stack[1])
-			stack[0] = boolToUint64(p.api.SetPlayerHealth(uuid, health) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.SetPlayerHealth(callCtx, uuid, health)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR, extism.ValueTypeF32},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -225,10 +323,13 @@ func (p *FunctionProvider) createSetPlayerHealthFunction(ctx *PluginContext) ext
 func (p *FunctionProvider) createSetPlayerGameModeFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnSetPlayerGamemode,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			uuid, _ := plugin.ReadString(stack[0])
 			gameMode := int32(stack[1])
-			stack[0] = boolToUint64(p.api.SetPlayerGameMode(uuid, gameMode) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.SetPlayerGameMode(callCtx, uuid, gameMode)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR, extism.ValueTypeI32},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -238,10 +339,13 @@ func (p *FunctionProvider) createSetPlayerGameModeFunction(ctx *PluginContext) e
 func (p *FunctionProvider) createGiveItemFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnGiveItem,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			data, _ := plugin.ReadBytes(stack[0])
 			req := deserializeGiveItemRequest(data)
-			stack[0] = boolToUint64(p.api.GiveItem(req.UUID, req.ItemType, req.Count, req.Metadata) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.GiveItem(callCtx, req.UUID, req.ItemType, req.Count, req.Metadata)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -251,11 +355,21 @@ func (p *FunctionProvider) createGiveItemFunction(ctx *PluginContext) extism.Hos
 func (p *FunctionProvider) createGetBlockFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnGetBlock,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			data, _ := plugin.ReadBytes(stack[0])
 			req := deserializeGetBlockRequest(data)
-			block, err := p.api.GetBlock(req.World, req.X, req.Y, req.Z)
-			if err != nil {
+
+			var block BlockInfo
+			status, callErr := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				var err error
+				block, err = p.api.GetBlock(callCtx, req.World, req.X, req.Y, req.Z)
+				return err
+			})
+			if status != CallOK && status != CallFailed {
+				stack[0] = writeStatusError(plugin, status)
+				return
+			}
+			if callErr != nil {
 				stack[0] = 0
 				return
 			}
@@ -270,53 +384,13 @@ func (p *FunctionProvider) createGetBlockFunction(ctx *PluginContext) extism.Hos
 func (p *FunctionProvider) createSetBlockFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnSetBlock,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			data, _ := plugin.ReadBytes(stack[0])
 			req := deserializeSetBlockRequest(data)
-			stack[0] = boolToUint64(p.api.SetBlock(req.World, req.X, req.Y, req.Z, req.BlockType, req.Properties) == nil)
-		},
-		[]extism.ValueType{extism.ValueTypePTR},
-		[]extism.ValueType{extism.ValueTypeI32},
-	)
-}
-
-func (p *FunctionProvider) createStorageGetFunction(ctx *PluginContext) extism.HostFunction {
-	return extism.NewHostFunctionWithStack(
-		fnStorageGet,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
-			key, _ := plugin.ReadString(stack[0])
-			value, found := p.api.StorageGet(ctx.PluginID, key)
-			if !found {
-				stack[0] = 0
-				return
-			}
-			offset, _ := plugin.WriteBytes(value)
-			stack[0] = offset
-		},
-		[]extism.ValueType{extism.ValueTypePTR},
-		[]extism.ValueType{extism.ValueTypePTR},
-	)
-}
-
-func (p *FunctionProvider) createStorageSetFunction(ctx *PluginContext) extism.HostFunction {
-	return extism.NewHostFunctionWithStack(
-		fnStorageSet,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
-			key, _ := plugin.ReadString(stack[0])
-			value, _ := plugin.ReadBytes(stack[1])
-			stack[0] = boolToUint64(p.api.StorageSet(ctx.PluginID, key, value) == nil)
-		},
-		[]extism.ValueType{extism.ValueTypePTR, extism.ValueTypePTR},
-		[]extism.ValueType{extism.ValueTypeI32},
-	)
-}
-
-func (p *FunctionProvider) createStorageDeleteFunction(ctx *PluginContext) extism.HostFunction {
-	return extism.NewHostFunctionWithStack(
-		fnStorageDelete,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
-			key, _ := plugin.ReadString(stack[0])
-			stack[0] = boolToUint64(p.api.StorageDelete(ctx.PluginID, key) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.SetBlock(callCtx, req.World, req.X, req.Y, req.Z, req.BlockType, req.Properties)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -326,10 +400,13 @@ func (p *FunctionProvider) createStorageDeleteFunction(ctx *PluginContext) extis
 func (p *FunctionProvider) createScheduleTaskFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnScheduleTask,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			data, _ := plugin.ReadBytes(stack[0])
 			req := deserializeScheduleTaskRequest(data)
-			stack[0] = boolToUint64(p.api.ScheduleTask(ctx.PluginID, req.TaskID, req.DelayMs, req.Data) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.ScheduleTask(callCtx, ctx.PluginID, req.TaskID, req.DelayMs, req.Data)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR},
 		[]extism.ValueType{extism.ValueTypeI32},
@@ -339,18 +416,35 @@ func (p *FunctionProvider) createScheduleTaskFunction(ctx *PluginContext) extism
 func (p *FunctionProvider) createCancelTaskFunction(ctx *PluginContext) extism.HostFunction {
 	return extism.NewHostFunctionWithStack(
 		fnCancelTask,
-		func(_ context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
+		func(hostCtx context.Context, plugin *extism.CurrentPlugin, stack []uint64) {
 			taskID, _ := plugin.ReadString(stack[0])
-			stack[0] = boolToUint64(p.api.CancelTask(ctx.PluginID, taskID) == nil)
+			status, _ := p.runWithTimeout(hostCtx, func(callCtx context.Context) error {
+				return p.api.CancelTask(callCtx, ctx.PluginID, taskID)
+			})
+			stack[0] = statusUint64(status)
 		},
 		[]extism.ValueType{extism.ValueTypePTR},
 		[]extism.ValueType{extism.ValueTypeI32},
 	)
 }
 
-func boolToUint64(b bool) uint64 {
-	if b {
-		return 1
+// writeStatusError writes a small {"error": "timeout"|"cancelled"} envelope
+// for a pointer-returning host function so a guest can distinguish "the call
+// didn't finish" from "it finished and found nothing" (offset 0) without a
+// second status channel. status is always CallTimeout or CallCancelled here;
+// CallOK/CallFailed are handled by their callers before reaching this.
+func writeStatusError(plugin *extism.CurrentPlugin, status CallStatus) uint64 {
+	reason := "cancelled"
+	if status == CallTimeout {
+		reason = "timeout"
+	}
+	data, err := json.Marshal(map[string]string{"error": reason})
+	if err != nil {
+		return 0
+	}
+	offset, err := plugin.WriteBytes(data)
+	if err != nil {
+		return 0
 	}
-	return 0
+	return offset
 }
@@ -7,6 +7,11 @@ import (
 	"regexp"
 )
 
+// PluginID is a Manifest.ID value, distinguished from a plain string so
+// Manager.ResolveDependencies' return type is self-documenting at call
+// sites.
+type PluginID string
+
 type Version struct {
 	Major int `toml:"major" json:"major"`
 	Minor int `toml:"minor" json:"minor"`
@@ -17,6 +22,20 @@ func (v Version) String() string {
 	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
 }
 
+// ABICompatible reports whether a plugin built against apiVersion can be
+// loaded against the host's current ABI major version. Only Major needs to
+// match: Minor/Patch bumps to the host ABI are additive, so an older plugin
+// built against a lower minor/patch still works.
+func ABICompatible(apiVersion Version) bool {
+	return apiVersion.Major == CurrentABIVersion.Major
+}
+
+// CurrentABIVersion is the plugin API version this host implements, checked
+// against a plugin's Manifest.APIVersion before it is loaded. It tracks
+// host.ABIVersion's major number; bump both together when the host function
+// wire format changes in a breaking way.
+var CurrentABIVersion = Version{Major: 1, Minor: 0, Patch: 0}
+
 func (v Version) Compare(other Version) int {
 	if c := cmp.Compare(v.Major, other.Major); c != 0 {
 		return c
@@ -60,6 +79,22 @@ const (
 	EventCommand        EventType = "command"
 	EventSignEdit       EventType = "sign_edit"
 	EventServerTransfer EventType = "server_transfer"
+
+	// EventPacketReceive and EventPacketSend carry raw gophertunnel packets
+	// crossing the connection boundary, rather than the higher-level
+	// player.Handler callbacks above. They let a plugin observe or cancel
+	// packets (e.g. packet.LevelChunk, packet.ChangeDimension,
+	// packet.SystemChatMessage) that have no dedicated Handle* method.
+	EventPacketReceive EventType = "packet_receive"
+	EventPacketSend    EventType = "packet_send"
+
+	// EventChunkLoad, EventChunkUnload and EventChunkModify let a plugin
+	// observe world generation/persistence traffic and block deltas at
+	// chunk granularity, instead of polling WorldAdapter.GetBlock per
+	// coordinate.
+	EventChunkLoad   EventType = "chunk_load"
+	EventChunkUnload EventType = "chunk_unload"
+	EventChunkModify EventType = "chunk_modify"
 )
 
 type Priority int
@@ -79,23 +114,47 @@ type EventSubscription struct {
 	IgnoreCancelled bool      `toml:"ignore_cancelled" json:"ignore_cancelled"`
 }
 
+// Dependency declares that a plugin requires another plugin, identified by
+// ID, whose Version satisfies Constraint (e.g. "^1.4", ">=1.2.0, <2.0.0", or
+// a bare "1.2.3" meaning exactly that version). A dependency the host can't
+// resolve is ignored rather than failing the load when Optional is set.
 type Dependency struct {
-	ID       string  `toml:"id" json:"id"`
-	Version  Version `toml:"version" json:"version"`
-	Optional bool    `toml:"optional" json:"optional"`
+	ID         string            `toml:"id" json:"id"`
+	Constraint VersionConstraint `toml:"version" json:"version"`
+	Optional   bool              `toml:"optional" json:"optional"`
 }
 
 type ResourceLimits struct {
 	MaxMemoryMB    int64  `toml:"max_memory_mb" json:"max_memory_mb"`
 	MaxExecutionMs int64  `toml:"max_execution_ms" json:"max_execution_ms"`
 	MaxFuel        uint64 `toml:"max_fuel" json:"max_fuel"`
+
+	// MaxStorageBytes caps the cumulative key+value bytes a plugin may hold
+	// in Storage, enforced by manager.QuotaStorage. Like a zero
+	// MaxMemoryMB/MaxExecutionMs/MaxFuel, a zero here falls back to
+	// DefaultLimits (capped by GlobalLimits) in Config.GetEffectiveLimits;
+	// QuotaStorage treats an effective limit of zero as unlimited.
+	MaxStorageBytes int64 `toml:"max_storage_bytes" json:"max_storage_bytes"`
+
+	// Supervise, MaxRestarts and BackoffBaseMs control whether a crashed
+	// plugin (a trapped or errored handle_event/plugin_init call) is
+	// automatically rebuilt. BackoffBaseMs is milliseconds rather than a
+	// time.Duration so this struct stays plain-TOML-decodable like
+	// MaxExecutionMs above; a restart attempt waits
+	// BackoffBaseMs*2^(attempt-1), capped, before rebuilding the instance.
+	Supervise     bool  `toml:"supervise" json:"supervise"`
+	MaxRestarts   int   `toml:"max_restarts" json:"max_restarts"`
+	BackoffBaseMs int64 `toml:"backoff_base_ms" json:"backoff_base_ms"`
 }
 
 func DefaultResourceLimits() ResourceLimits {
 	return ResourceLimits{
-		MaxMemoryMB:    64,
-		MaxExecutionMs: 100,
-		MaxFuel:        1_000_000,
+		MaxMemoryMB:     64,
+		MaxExecutionMs:  100,
+		MaxFuel:         1_000_000,
+		MaxRestarts:     5,
+		BackoffBaseMs:   1000,
+		MaxStorageBytes: 16 << 20,
 	}
 }
 
@@ -38,15 +38,22 @@ type Metrics struct {
 	PeakMemoryBytes      uint64
 	EventsHandled        map[EventType]uint64
 	EventsCancelled      map[EventType]uint64
+	EventDurations       map[EventType]time.Duration
 	ErrorCount           uint64
 	LastError            string
 	LastErrorTime        time.Time
+	RestartCount         uint64
+	LastRestartAt        time.Time
+	ReloadCount          uint64
+	LastReloadAt         time.Time
+	ConsecutiveFailures  uint64
 }
 
 func NewMetrics() *Metrics {
 	return &Metrics{
 		EventsHandled:   make(map[EventType]uint64),
 		EventsCancelled: make(map[EventType]uint64),
+		EventDurations:  make(map[EventType]time.Duration),
 	}
 }
 
@@ -73,6 +80,38 @@ func (m *Metrics) RecordEvent(event EventType, cancelled bool) {
 	}
 }
 
+// RecordEventDuration accumulates how long a single on_<event> call took, so
+// AvgProcessingTimeUs can report a per-event-type average instead of the
+// overall AverageExecutionTime across every call a plugin handles.
+func (m *Metrics) RecordEventDuration(event EventType, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.EventDurations[event] += d
+}
+
+// EventsProcessed reports how many times event was dispatched to this
+// plugin, regardless of outcome.
+func (m *Metrics) EventsProcessed(event EventType) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.EventsHandled[event]
+}
+
+// AvgProcessingTimeUs reports the average on_<event> handler duration in
+// microseconds, or 0 if event has never been recorded.
+func (m *Metrics) AvgProcessingTimeUs(event EventType) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := m.EventsHandled[event]
+	if count == 0 {
+		return 0
+	}
+	return uint64(m.EventDurations[event].Microseconds()) / count
+}
+
 func (m *Metrics) RecordError(err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -82,6 +121,49 @@ func (m *Metrics) RecordError(err error) {
 	m.LastErrorTime = time.Now()
 }
 
+// RecordRestart tracks a supervisor-driven instance rebuild after a crash,
+// so operators can tell a flapping plugin from a merely slow one.
+func (m *Metrics) RecordRestart() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.RestartCount++
+	m.LastRestartAt = time.Now()
+}
+
+// RecordFailure increments ConsecutiveFailures, the run of health-check or
+// crash failures since the last successful call or health check. Unlike
+// ErrorCount, which never resets, it can distinguish a plugin that failed
+// once a week ago from one that's failing right now. Nothing in this module
+// reads it to stop restarts: internal/manager's supervisor bounds restart
+// attempts by ResourceLimits.MaxRestarts alone, with no circuit breaker.
+func (m *Metrics) RecordFailure() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ConsecutiveFailures++
+}
+
+// ResetFailures zeroes ConsecutiveFailures after a successful call or health
+// check breaks a failure streak.
+func (m *Metrics) ResetFailures() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ConsecutiveFailures = 0
+}
+
+// RecordReload tracks a dev-mode hot-reload of this plugin's WASM module,
+// distinct from RecordRestart: a restart follows a crash, a reload follows
+// a developer saving their source.
+func (m *Metrics) RecordReload() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ReloadCount++
+	m.LastReloadAt = time.Now()
+}
+
 func (m *Metrics) RecordMemory(bytes uint64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -92,11 +174,15 @@ func (m *Metrics) RecordMemory(bytes uint64) {
 	}
 }
 
-func (m *Metrics) Snapshot() Metrics {
+// Snapshot returns a point-in-time copy of m safe to read, persist, or hand
+// off to another goroutine without holding m.mu. It returns a *Metrics
+// rather than a Metrics value so copying the snapshot around never copies
+// the (unused, always-zero) mutex embedded in Metrics itself.
+func (m *Metrics) Snapshot() *Metrics {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return Metrics{
+	return &Metrics{
 		TotalCalls:           m.TotalCalls,
 		TotalExecutionTime:   m.TotalExecutionTime,
 		AverageExecutionTime: m.AverageExecutionTime,
@@ -107,15 +193,28 @@ func (m *Metrics) Snapshot() Metrics {
 		ErrorCount:           m.ErrorCount,
 		LastError:            m.LastError,
 		LastErrorTime:        m.LastErrorTime,
+		RestartCount:         m.RestartCount,
+		LastRestartAt:        m.LastRestartAt,
+		ReloadCount:          m.ReloadCount,
+		LastReloadAt:         m.LastReloadAt,
+		ConsecutiveFailures:  m.ConsecutiveFailures,
 		EventsHandled:        maps.Clone(m.EventsHandled),
 		EventsCancelled:      maps.Clone(m.EventsCancelled),
+		EventDurations:       maps.Clone(m.EventDurations),
 	}
 }
 
 type Info struct {
-	Manifest   *Manifest
-	State      State
-	Metrics    *Metrics
+	Manifest *Manifest
+	State    State
+	Metrics  *Metrics
+
+	// LastKnownState is the State most recently persisted to the manager's
+	// state store, restored on LoadAll before the live State above is
+	// touched. It lags State by design: State reflects what's true for this
+	// process right now, LastKnownState is what survives a restart.
+	LastKnownState State
+
 	LoadedAt   time.Time
 	EnabledAt  time.Time
 	DisabledAt time.Time
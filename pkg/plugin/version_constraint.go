@@ -0,0 +1,205 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionConstraint is a parsed plugin.Dependency version requirement,
+// following npm/cargo-style syntax: a bare "1.2.3" or "=1.2.3" is exact,
+// ">=1.2.0, <2.0.0" ANDs comma-separated comparisons, "^1.4" allows any
+// version compatible per semver ("^1.4" = ">=1.4.0,<2.0.0", "^0.2.3" =
+// ">=0.2.3,<0.3.0"), and "~1.2.3" allows patch-level changes only
+// ("~1.2.3" = ">=1.2.3,<1.3.0").
+type VersionConstraint struct {
+	raw     string
+	clauses []versionClause
+}
+
+type constraintOp int
+
+const (
+	opEQ constraintOp = iota
+	opGTE
+	opGT
+	opLTE
+	opLT
+)
+
+type versionClause struct {
+	op      constraintOp
+	version Version
+}
+
+func (cl versionClause) matches(v Version) bool {
+	c := v.Compare(cl.version)
+	switch cl.op {
+	case opEQ:
+		return c == 0
+	case opGTE:
+		return c >= 0
+	case opGT:
+		return c > 0
+	case opLTE:
+		return c <= 0
+	case opLT:
+		return c < 0
+	default:
+		return false
+	}
+}
+
+// Matches reports whether v satisfies every clause in c. An empty
+// constraint (the zero value) matches any version.
+func (c VersionConstraint) Matches(v Version) bool {
+	for _, cl := range c.clauses {
+		if !cl.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c VersionConstraint) String() string {
+	if c.raw == "" {
+		return "*"
+	}
+	return c.raw
+}
+
+func (c VersionConstraint) MarshalText() ([]byte, error) {
+	return []byte(c.raw), nil
+}
+
+func (c *VersionConstraint) UnmarshalText(text []byte) error {
+	parsed, err := ParseVersionConstraint(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// ParseVersionConstraint parses a dependency version expression. A bare
+// version with no operator (e.g. "1.2.3") is treated as "=1.2.3" for
+// backward compatibility with manifests written before constraints existed.
+func ParseVersionConstraint(s string) (VersionConstraint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return VersionConstraint{}, nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "^"); ok {
+		v, _, err := parseVersionComponents(strings.TrimSpace(rest))
+		if err != nil {
+			return VersionConstraint{}, fmt.Errorf("invalid caret constraint %q: %w", s, err)
+		}
+		return caretConstraint(s, v), nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "~"); ok {
+		v, n, err := parseVersionComponents(strings.TrimSpace(rest))
+		if err != nil {
+			return VersionConstraint{}, fmt.Errorf("invalid tilde constraint %q: %w", s, err)
+		}
+		return tildeConstraint(s, v, n), nil
+	}
+
+	var clauses []versionClause
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return VersionConstraint{}, fmt.Errorf("invalid constraint %q: empty clause", s)
+		}
+		cl, err := parseClause(part)
+		if err != nil {
+			return VersionConstraint{}, fmt.Errorf("invalid constraint %q: %w", s, err)
+		}
+		clauses = append(clauses, cl)
+	}
+	if len(clauses) == 0 {
+		return VersionConstraint{}, fmt.Errorf("empty version constraint")
+	}
+	return VersionConstraint{raw: s, clauses: clauses}, nil
+}
+
+func parseClause(s string) (versionClause, error) {
+	op, rest := opEQ, s
+	switch {
+	case strings.HasPrefix(s, ">="):
+		op, rest = opGTE, s[2:]
+	case strings.HasPrefix(s, "<="):
+		op, rest = opLTE, s[2:]
+	case strings.HasPrefix(s, ">"):
+		op, rest = opGT, s[1:]
+	case strings.HasPrefix(s, "<"):
+		op, rest = opLT, s[1:]
+	case strings.HasPrefix(s, "="):
+		op, rest = opEQ, s[1:]
+	}
+
+	v, _, err := parseVersionComponents(strings.TrimSpace(rest))
+	if err != nil {
+		return versionClause{}, err
+	}
+	return versionClause{op: op, version: v}, nil
+}
+
+// caretConstraint builds ">=v,<upper" where upper bumps the leftmost
+// nonzero component of v, matching npm/cargo caret semantics.
+func caretConstraint(raw string, v Version) VersionConstraint {
+	upper := Version{Major: v.Major + 1}
+	switch {
+	case v.Major > 0:
+		upper = Version{Major: v.Major + 1}
+	case v.Minor > 0:
+		upper = Version{Minor: v.Minor + 1}
+	default:
+		upper = Version{Patch: v.Patch + 1}
+	}
+	return VersionConstraint{
+		raw: raw,
+		clauses: []versionClause{
+			{op: opGTE, version: v},
+			{op: opLT, version: upper},
+		},
+	}
+}
+
+// tildeConstraint builds ">=v,<upper" where upper bumps the component just
+// above the least-significant one given ("~1.2.3"/"~1.2" both cap at the
+// next minor, "~1" caps at the next major).
+func tildeConstraint(raw string, v Version, componentsGiven int) VersionConstraint {
+	upper := Version{Major: v.Major, Minor: v.Minor + 1}
+	if componentsGiven == 1 {
+		upper = Version{Major: v.Major + 1}
+	}
+	return VersionConstraint{
+		raw: raw,
+		clauses: []versionClause{
+			{op: opGTE, version: v},
+			{op: opLT, version: upper},
+		},
+	}
+}
+
+// parseVersionComponents parses "major[.minor[.patch]]" and reports how
+// many components were actually given, which tilde/caret parsing needs to
+// pick the right upper bound.
+func parseVersionComponents(s string) (Version, int, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, 0, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		nums[i] = n
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, len(parts), nil
+}
@@ -0,0 +1,62 @@
+package plugin
+
+import "testing"
+
+func TestParseVersionConstraintMatches(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    Version
+		want       bool
+	}{
+		{"1.2.3", Version{1, 2, 3}, true},
+		{"1.2.3", Version{1, 2, 4}, false},
+		{"=1.2.3", Version{1, 2, 3}, true},
+		{">=1.2.0, <2.0.0", Version{1, 9, 9}, true},
+		{">=1.2.0, <2.0.0", Version{2, 0, 0}, false},
+		{"^1.4", Version{1, 9, 0}, true},
+		{"^1.4", Version{2, 0, 0}, false},
+		{"^0.2.3", Version{0, 2, 9}, true},
+		{"^0.2.3", Version{0, 3, 0}, false},
+		{"~1.2.3", Version{1, 2, 9}, true},
+		{"~1.2.3", Version{1, 3, 0}, false},
+		{"", Version{9, 9, 9}, true},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseVersionConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseVersionConstraint(%q): %v", tt.constraint, err)
+		}
+		if got := c.Matches(tt.version); got != tt.want {
+			t.Errorf("%q.Matches(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionConstraintInvalid(t *testing.T) {
+	for _, s := range []string{"not-a-version", "^nope", "~1.2.3.4.5", ">=1.2.0,"} {
+		if _, err := ParseVersionConstraint(s); err == nil {
+			t.Errorf("ParseVersionConstraint(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestVersionConstraintRoundTrip(t *testing.T) {
+	c, err := ParseVersionConstraint(">=1.2.0, <2.0.0")
+	if err != nil {
+		t.Fatalf("ParseVersionConstraint: %v", err)
+	}
+
+	text, err := c.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %v", err)
+	}
+
+	var roundTripped VersionConstraint
+	if err := roundTripped.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if !roundTripped.Matches(Version{1, 5, 0}) {
+		t.Errorf("round-tripped constraint %q didn't match 1.5.0", roundTripped)
+	}
+}
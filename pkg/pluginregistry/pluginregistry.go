@@ -0,0 +1,208 @@
+// Package pluginregistry declaratively installs plugin WASM files from
+// config-pinned URLs + checksums, separate from pkg/registry (which indexes
+// Dragonfly's own block/item types) and from internal/manager's
+// RemoteSource index client (which resolves plugins indirectly through a
+// signed index.json rather than pinning each one by URL in Config).
+package pluginregistry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/verify"
+)
+
+// PluginSource pins a single plugin to a URL + checksum, declared as a
+// `[[plugins]]` table in Config. SignatureURL is optional; it's only
+// fetched and checked when the Manager was built with requireSigned set.
+type PluginSource struct {
+	ID           string `toml:"id"`
+	URL          string `toml:"url"`
+	SHA256       string `toml:"sha256"`
+	SignatureURL string `toml:"signature_url"`
+}
+
+// Commander is the interface a CLI surface needs to implement "plugins
+// list" / "plugins update" against a Manager, without depending on its
+// concrete type.
+type Commander interface {
+	ListSources() []PluginSource
+	Sync(ctx context.Context) error
+}
+
+// Manager downloads and verifies every configured PluginSource into
+// PluginDir, caching verified downloads in CacheDir keyed by checksum so a
+// repeated Sync only re-fetches plugins whose declared SHA256 changed.
+type Manager struct {
+	pluginDir     string
+	cacheDir      string
+	sources       []PluginSource
+	requireSigned bool
+	trustedKeys   []ed25519.PublicKey
+	httpClient    *http.Client
+	logger        *zap.Logger
+}
+
+var _ Commander = (*Manager)(nil)
+
+// New builds a Manager. trustedKeyStrs entries may be a PEM-encoded public
+// key block or a raw key that's base64 standard-encoded; an entry in
+// neither form is skipped with a warning rather than failing the whole
+// call, since one malformed key in Config shouldn't prevent Sync from
+// verifying against the rest.
+func New(pluginDir, cacheDir string, sources []PluginSource, requireSigned bool, trustedKeyStrs []string, logger *zap.Logger) *Manager {
+	m := &Manager{
+		pluginDir:     pluginDir,
+		cacheDir:      cacheDir,
+		sources:       sources,
+		requireSigned: requireSigned,
+		httpClient:    http.DefaultClient,
+		logger:        logger.Named("plugin-registry"),
+	}
+	for _, s := range trustedKeyStrs {
+		key, err := verify.ParseEd25519PublicKey(s)
+		if err != nil {
+			m.logger.Warn("skipping unparseable trusted public key", zap.Error(err))
+			continue
+		}
+		m.trustedKeys = append(m.trustedKeys, key)
+	}
+	return m
+}
+
+// ListSources reports the plugin sources this Manager was configured with.
+func (m *Manager) ListSources() []PluginSource {
+	return m.sources
+}
+
+// Sync downloads every source missing or outdated from PluginDir, verifying
+// each against its declared SHA256 (and, if requireSigned, a detached
+// Ed25519 signature from one of the configured trusted keys) before
+// installing it. A single source's failure is collected and reported
+// alongside the rest rather than aborting the whole sync.
+func (m *Manager) Sync(ctx context.Context) error {
+	if err := os.MkdirAll(m.cacheDir, 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+
+	var errs []error
+	for _, src := range m.sources {
+		if err := m.syncOne(ctx, src); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", src.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) syncOne(ctx context.Context, src PluginSource) error {
+	cachePath := filepath.Join(m.cacheDir, src.SHA256+".wasm")
+
+	data, err := cachedDataMatching(cachePath, src.SHA256)
+	if err != nil {
+		data, err = m.download(ctx, src.URL, src.SHA256, cachePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.requireSigned {
+		if err := m.verifySignature(ctx, src, data); err != nil {
+			return fmt.Errorf("verify signature: %w", err)
+		}
+	}
+
+	installPath := filepath.Join(m.pluginDir, src.ID, "plugin.wasm")
+	if installedDataMatches(installPath, src.SHA256) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(installPath), 0o755); err != nil {
+		return fmt.Errorf("create plugin directory: %w", err)
+	}
+	tmp := installPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write plugin wasm: %w", err)
+	}
+	if err := os.Rename(tmp, installPath); err != nil {
+		return fmt.Errorf("install plugin wasm: %w", err)
+	}
+
+	m.logger.Info("synced plugin from registry", zap.String("id", src.ID), zap.String("url", src.URL))
+	return nil
+}
+
+// cachedDataMatching reads path if it exists and hashes to wantSHA256,
+// letting syncOne skip a re-download on cache hits.
+func cachedDataMatching(path, wantSHA256 string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if verify.SHA256Hex(data) != wantSHA256 {
+		return nil, fmt.Errorf("cached file %s no longer matches sha256 %s", path, wantSHA256)
+	}
+	return data, nil
+}
+
+// installedDataMatches reports whether path is already installed with
+// content matching wantSHA256, so a verified-but-unchanged plugin isn't
+// rewritten on every Sync.
+func installedDataMatches(path, wantSHA256 string) bool {
+	return verify.FileSHA256Matches(path, wantSHA256)
+}
+
+func (m *Manager) download(ctx context.Context, url, wantSHA256, cachePath string) ([]byte, error) {
+	data, err := verify.Fetch(ctx, m.httpClient, url)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+
+	if got := verify.SHA256Hex(data); got != wantSHA256 {
+		return nil, fmt.Errorf("download %s: sha256 mismatch: got %s, want %s", url, got, wantSHA256)
+	}
+
+	tmp := cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write cached wasm: %w", err)
+	}
+	if err := os.Rename(tmp, cachePath); err != nil {
+		return nil, fmt.Errorf("cache downloaded wasm: %w", err)
+	}
+
+	return data, nil
+}
+
+// verifySignature fetches src.SignatureURL and checks the detached
+// signature it contains (raw bytes or base64-encoded) against data, trying
+// every configured trusted key until one verifies.
+func (m *Manager) verifySignature(ctx context.Context, src PluginSource, data []byte) error {
+	if src.SignatureURL == "" {
+		return errors.New("signature required but no signature_url configured")
+	}
+	if len(m.trustedKeys) == 0 {
+		return errors.New("signature required but no trusted public keys configured")
+	}
+
+	raw, err := verify.Fetch(ctx, m.httpClient, src.SignatureURL)
+	if err != nil {
+		return fmt.Errorf("fetch signature: %w", err)
+	}
+
+	sig := raw
+	if decoded, err := base64.StdEncoding.DecodeString(string(raw)); err == nil {
+		sig = decoded
+	}
+
+	if !verify.Ed25519VerifyAny(m.trustedKeys, data, sig) {
+		return errors.New("signature did not verify against any trusted public key")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package registry indexes the block and item types registered with
+// Dragonfly at server start so adapters can resolve an encoded Bedrock
+// identifier (plus NBT state properties) back to a concrete world.Block or
+// world.Item, rather than guessing from reflection or giving up entirely.
+package registry
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// Registry resolves encoded block/item identifiers to their concrete
+// Dragonfly values.
+type Registry struct {
+	blocks map[string]world.Block
+	items  map[string]world.Item
+}
+
+// New builds a Registry by walking every block and item Dragonfly has
+// registered. It must be called after Dragonfly's own init-time
+// registrations have run (i.e. not from a package init function).
+func New() *Registry {
+	r := &Registry{
+		blocks: make(map[string]world.Block),
+		items:  make(map[string]world.Item),
+	}
+	r.indexBlocks()
+	r.indexItems()
+	return r
+}
+
+func (r *Registry) indexBlocks() {
+	for _, b := range world.Blocks() {
+		name, properties := b.EncodeBlock()
+		r.blocks[blockKey(name, properties)] = b
+	}
+}
+
+func (r *Registry) indexItems() {
+	for _, i := range world.Items() {
+		name := itemName(i)
+		if name == "" {
+			continue
+		}
+		r.items[name] = i
+	}
+}
+
+// BlockByName resolves an encoded block identifier (e.g. "minecraft:oak_stairs")
+// and its NBT state properties (e.g. facing_direction, upside_down_bit) to
+// the concrete world.Block registered for that exact state.
+func (r *Registry) BlockByName(name string, props map[string]any) (world.Block, bool) {
+	b, ok := r.blocks[blockKey(name, props)]
+	return b, ok
+}
+
+// ItemByName resolves an encoded Bedrock item identifier to the concrete
+// world.Item registered for it.
+func (r *Registry) ItemByName(name string) (world.Item, bool) {
+	i, ok := r.items[name]
+	return i, ok
+}
+
+// BlockName returns the encoded identifier and NBT properties for b, mainly
+// useful for round-tripping a block looked up elsewhere back through the
+// wire format.
+func BlockName(b world.Block) (string, map[string]any) {
+	if b == nil {
+		return "air", nil
+	}
+	return b.EncodeBlock()
+}
+
+// ItemName returns the encoded identifier for i, or "unknown" if i does not
+// expose one.
+func ItemName(i world.Item) string {
+	if i == nil {
+		return "air"
+	}
+	if name := itemName(i); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// NameForItem resolves i to its encoded identifier. Unlike the package-level
+// ItemName, when i's own EncodeNBT carries no name it falls back to comparing
+// i's concrete Go type against the indexed items. Only items that implement
+// world.NBTer and encode a name are indexed, so an item that doesn't
+// implement world.NBTer still resolves to "unknown".
+func (r *Registry) NameForItem(i world.Item) string {
+	if i == nil {
+		return "air"
+	}
+	if name := itemName(i); name != "" {
+		return name
+	}
+	want := reflect.TypeOf(i)
+	for name, candidate := range r.items {
+		if reflect.TypeOf(candidate) == want {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+func itemName(i world.Item) string {
+	enc, ok := i.(world.NBTer)
+	if !ok {
+		return ""
+	}
+	name, ok := enc.EncodeNBT()["name"].(string)
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// blockKey builds a stable, order-independent key for a block's encoded
+// name and state properties.
+func blockKey(name string, properties map[string]any) string {
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		fmt.Fprintf(&b, ";%s=%v", k, properties[k])
+	}
+	return b.String()
+}
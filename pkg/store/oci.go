@@ -0,0 +1,435 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/EinBexiii/dragonfly-wasm/pkg/plugin"
+)
+
+const (
+	// WASMLayerMediaType identifies the OCI layer blob holding the plugin's
+	// raw WASM bytes.
+	WASMLayerMediaType = "application/vnd.wasm.content.layer.v1+wasm"
+	// ConfigMediaType identifies the OCI config blob holding PluginConfig -
+	// the exports/permissions/limits metadata a plugin is pushed with.
+	ConfigMediaType = "application/vnd.wasm.config.v1+json"
+	// ManifestLayerMediaType identifies the layer blob holding the plugin's
+	// plugin.toml, so a pull can reconstruct a full DiscoveredPlugin without
+	// a separate out-of-band fetch.
+	ManifestLayerMediaType = "application/vnd.dragonfly.plugin.manifest.v1+toml"
+	// manifestMediaType is sent as the Accept header on manifest fetches and
+	// as MediaType on manifests this client pushes.
+	manifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// PluginConfig is the OCI config blob content: everything about a plugin an
+// installer needs before it ever runs the WASM, mirroring the subset of
+// plugin.Manifest that matters for a trust decision rather than the whole
+// manifest (Authors/Website/Description aren't needed to install safely).
+type PluginConfig struct {
+	Exports     []string              `json:"exports"`
+	Permissions []string              `json:"permissions"`
+	Limits      plugin.ResourceLimits `json:"limits"`
+}
+
+// ociDescriptor is an OCI content descriptor: a typed pointer at a blob by
+// digest and size.
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// ociManifest is the subset of the OCI image manifest schema this client
+// reads and writes: a config descriptor plus the WASM content layer and
+// plugin.toml layer that make up one plugin artifact.
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// Reference names one plugin artifact in a registry: Repository/Tag for a
+// mutable alias ("myregistry.example.com/plugins/chat:latest"), or
+// Repository/Digest for an immutable pin ("plugins/chat@sha256:...").
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     Digest
+}
+
+// String renders ref back to the form ParseReference accepts.
+func (ref Reference) String() string {
+	host := ref.Registry + "/" + ref.Repository
+	if ref.Digest != "" {
+		return host + "@" + string(ref.Digest)
+	}
+	return host + ":" + ref.Tag
+}
+
+// ParseReference parses s as "registry/repo/name:tag" or
+// "registry/repo/name@sha256:...". A reference with no registry component
+// (just "repo/name:tag") is accepted with Registry left empty; callers that
+// need a default registry (e.g. from Config) should set it themselves.
+func ParseReference(s string) (Reference, error) {
+	hostAndRest := s
+	var digest Digest
+	var tag string
+
+	if i := strings.LastIndex(s, "@"); i != -1 {
+		hostAndRest = s[:i]
+		digest = Digest(s[i+1:])
+	} else if i := strings.LastIndex(s, ":"); i != -1 && i > strings.LastIndex(s, "/") {
+		hostAndRest = s[:i]
+		tag = s[i+1:]
+	} else {
+		tag = "latest"
+	}
+
+	parts := strings.Split(hostAndRest, "/")
+	if len(parts) < 2 {
+		return Reference{}, fmt.Errorf("invalid reference %q: expected at least repo/name", s)
+	}
+
+	registry := ""
+	repo := hostAndRest
+	if strings.Contains(parts[0], ".") || strings.Contains(parts[0], ":") || parts[0] == "localhost" {
+		registry = parts[0]
+		repo = strings.Join(parts[1:], "/")
+	}
+
+	return Reference{Registry: registry, Repository: repo, Tag: tag, Digest: digest}, nil
+}
+
+// AuthProvider returns the Authorization header value (e.g. "Bearer <token>"
+// or "Basic <creds>") to send to registry, or "" to send no Authorization
+// header at all. Client passes the value through verbatim - it neither
+// inspects nor caches credentials itself.
+type AuthProvider func(ctx context.Context, registry string) (string, error)
+
+// ProgressFunc is called as Pull/Push move bytes, with stage naming what's
+// in flight ("manifest", "config", "layer") and done/total in bytes (total
+// may be 0 if the registry didn't report Content-Length).
+type ProgressFunc func(stage string, done, total int64)
+
+// Client speaks the read/write subset of the OCI distribution protocol
+// (https://github.com/opencontainers/distribution-spec) this module needs:
+// fetching and pushing a WASM artifact manifest plus its config blob and its
+// two layer blobs (the WASM module and plugin.toml).
+type Client struct {
+	httpClient *http.Client
+	auth       AuthProvider
+}
+
+// NewClient builds a Client. auth may be nil, meaning every request is sent
+// unauthenticated.
+func NewClient(httpClient *http.Client, auth AuthProvider) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{httpClient: httpClient, auth: auth}
+}
+
+func noopProgress(string, int64, int64) {}
+
+// PullResult is what a successful Pull retrieved, as digests into store
+// rather than raw bytes - the caller re-reads them via Store.GetBlob, which
+// re-verifies each one's digest on every read rather than trusting the copy
+// Pull already checked.
+type PullResult struct {
+	ManifestDigest     Digest
+	WASMDigest         Digest
+	ManifestTOMLDigest Digest
+	Config             PluginConfig
+}
+
+// Pull fetches ref's manifest and all three of its blobs (OCI config, WASM
+// layer, plugin.toml layer) into store, verifying each blob's digest against
+// what the manifest declared. On success it records ref.Tag (if set) as an
+// alias pointing at the manifest digest, so a later Resolve(ref.Repository)
+// or Rollback can find it again.
+func (c *Client) Pull(ctx context.Context, s *Store, ref Reference, progress ProgressFunc) (PullResult, error) {
+	if progress == nil {
+		progress = noopProgress
+	}
+
+	manifestData, manifestDigest, err := c.fetchManifest(ctx, ref)
+	if err != nil {
+		return PullResult{}, fmt.Errorf("fetch manifest: %w", err)
+	}
+	progress("manifest", int64(len(manifestData)), int64(len(manifestData)))
+
+	var ociMan ociManifest
+	if err := json.Unmarshal(manifestData, &ociMan); err != nil {
+		return PullResult{}, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	configData, err := c.fetchBlob(ctx, ref, Digest(ociMan.Config.Digest), progress, "config")
+	if err != nil {
+		return PullResult{}, fmt.Errorf("fetch config blob: %w", err)
+	}
+	var cfg PluginConfig
+	if err := json.Unmarshal(configData, &cfg); err != nil {
+		return PullResult{}, fmt.Errorf("parse config blob: %w", err)
+	}
+	if _, err := s.PutBlob(configData); err != nil {
+		return PullResult{}, fmt.Errorf("store config blob: %w", err)
+	}
+
+	wasmLayer, err := findLayer(ociMan, WASMLayerMediaType)
+	if err != nil {
+		return PullResult{}, err
+	}
+	wasmData, err := c.fetchBlob(ctx, ref, Digest(wasmLayer.Digest), progress, "layer")
+	if err != nil {
+		return PullResult{}, fmt.Errorf("fetch wasm layer: %w", err)
+	}
+	wasmDigest, err := s.PutBlob(wasmData)
+	if err != nil {
+		return PullResult{}, fmt.Errorf("store wasm layer: %w", err)
+	}
+
+	manifestLayer, err := findLayer(ociMan, ManifestLayerMediaType)
+	if err != nil {
+		return PullResult{}, err
+	}
+	manifestTOML, err := c.fetchBlob(ctx, ref, Digest(manifestLayer.Digest), progress, "manifest-toml")
+	if err != nil {
+		return PullResult{}, fmt.Errorf("fetch manifest layer: %w", err)
+	}
+	manifestTOMLDigest, err := s.PutBlob(manifestTOML)
+	if err != nil {
+		return PullResult{}, fmt.Errorf("store manifest layer: %w", err)
+	}
+
+	if _, err := s.PutBlob(manifestData); err != nil {
+		return PullResult{}, fmt.Errorf("store manifest: %w", err)
+	}
+
+	if ref.Tag != "" {
+		if err := s.SetAlias(ref.Repository, manifestDigest); err != nil {
+			return PullResult{}, fmt.Errorf("record alias: %w", err)
+		}
+	}
+
+	return PullResult{
+		ManifestDigest:     manifestDigest,
+		WASMDigest:         wasmDigest,
+		ManifestTOMLDigest: manifestTOMLDigest,
+		Config:             cfg,
+	}, nil
+}
+
+// Push uploads wasmData, manifestTOML, and cfg as a new manifest for ref,
+// via the OCI monolithic blob upload (one POST to open the session, one PUT
+// with the full body to complete it - no chunking, since plugin WASM blobs
+// are small enough that multi-chunk uploads aren't worth the added protocol
+// surface).
+func (c *Client) Push(ctx context.Context, ref Reference, wasmData, manifestTOML []byte, cfg PluginConfig, progress ProgressFunc) (Digest, error) {
+	if progress == nil {
+		progress = noopProgress
+	}
+
+	configData, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("encode config: %w", err)
+	}
+	configDigest := ComputeDigest(configData)
+	if err := c.pushBlob(ctx, ref, configData); err != nil {
+		return "", fmt.Errorf("push config blob: %w", err)
+	}
+	progress("config", int64(len(configData)), int64(len(configData)))
+
+	wasmDigest := ComputeDigest(wasmData)
+	if err := c.pushBlob(ctx, ref, wasmData); err != nil {
+		return "", fmt.Errorf("push wasm layer: %w", err)
+	}
+	progress("layer", int64(len(wasmData)), int64(len(wasmData)))
+
+	manifestTOMLDigest := ComputeDigest(manifestTOML)
+	if err := c.pushBlob(ctx, ref, manifestTOML); err != nil {
+		return "", fmt.Errorf("push manifest layer: %w", err)
+	}
+	progress("manifest-toml", int64(len(manifestTOML)), int64(len(manifestTOML)))
+
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     manifestMediaType,
+		Config:        ociDescriptor{MediaType: ConfigMediaType, Digest: string(configDigest), Size: int64(len(configData))},
+		Layers: []ociDescriptor{
+			{MediaType: WASMLayerMediaType, Digest: string(wasmDigest), Size: int64(len(wasmData))},
+			{MediaType: ManifestLayerMediaType, Digest: string(manifestTOMLDigest), Size: int64(len(manifestTOML))},
+		},
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("encode manifest: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, ref, http.MethodPut, manifestPath(ref, ref.Tag), bytes.NewReader(manifestData))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", manifestMediaType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("put manifest: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("put manifest: unexpected status %s", resp.Status)
+	}
+	progress("manifest", int64(len(manifestData)), int64(len(manifestData)))
+
+	return ComputeDigest(manifestData), nil
+}
+
+func (c *Client) fetchManifest(ctx context.Context, ref Reference) ([]byte, Digest, error) {
+	tagOrDigest := ref.Tag
+	if ref.Digest != "" {
+		tagOrDigest = string(ref.Digest)
+	}
+
+	req, err := c.newRequest(ctx, ref, http.MethodGet, manifestPath(ref, tagOrDigest), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", manifestMediaType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, ComputeDigest(data), nil
+}
+
+func (c *Client) fetchBlob(ctx context.Context, ref Reference, digest Digest, progress ProgressFunc, stage string) ([]byte, error) {
+	req, err := c.newRequest(ctx, ref, http.MethodGet, blobPath(ref, digest), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	progress(stage, int64(len(data)), resp.ContentLength)
+
+	if got := ComputeDigest(data); got != digest {
+		return nil, fmt.Errorf("blob digest mismatch: got %s, want %s", got, digest)
+	}
+	return data, nil
+}
+
+// pushBlob uploads data as a monolithic blob: POST to open an upload
+// session (following the Location the registry returns), then PUT the full
+// body with ?digest=<sha256> to complete it in one round trip.
+func (c *Client) pushBlob(ctx context.Context, ref Reference, data []byte) error {
+	startReq, err := c.newRequest(ctx, ref, http.MethodPost, uploadsPath(ref), nil)
+	if err != nil {
+		return err
+	}
+	startResp, err := c.httpClient.Do(startReq)
+	if err != nil {
+		return err
+	}
+	location := startResp.Header.Get("Location")
+	startResp.Body.Close()
+	if startResp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("start blob upload: unexpected status %s", startResp.Status)
+	}
+	if location == "" {
+		return fmt.Errorf("start blob upload: registry returned no Location header")
+	}
+
+	digest := ComputeDigest(data)
+	sep := "?"
+	if strings.Contains(location, "?") {
+		sep = "&"
+	}
+	putReq, err := c.newRequest(ctx, ref, http.MethodPut, "", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	putReq.URL, err = putReq.URL.Parse(location + sep + "digest=" + string(digest))
+	if err != nil {
+		return err
+	}
+	putReq.Header.Set("Content-Type", "application/octet-stream")
+
+	putResp, err := c.httpClient.Do(putReq)
+	if err != nil {
+		return err
+	}
+	defer putResp.Body.Close()
+	if putResp.StatusCode/100 != 2 {
+		return fmt.Errorf("complete blob upload: unexpected status %s", putResp.Status)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, ref Reference, method, path string, body io.Reader) (*http.Request, error) {
+	url := "https://" + ref.Registry + path
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.auth != nil {
+		header, err := c.auth(ctx, ref.Registry)
+		if err != nil {
+			return nil, fmt.Errorf("resolve auth: %w", err)
+		}
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+	}
+	return req, nil
+}
+
+func manifestPath(ref Reference, tagOrDigest string) string {
+	return fmt.Sprintf("/v2/%s/manifests/%s", ref.Repository, tagOrDigest)
+}
+
+func blobPath(ref Reference, digest Digest) string {
+	return fmt.Sprintf("/v2/%s/blobs/%s", ref.Repository, digest)
+}
+
+func uploadsPath(ref Reference) string {
+	return fmt.Sprintf("/v2/%s/blobs/uploads/", ref.Repository)
+}
+
+func findLayer(manifest ociManifest, mediaType string) (ociDescriptor, error) {
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == mediaType {
+			return layer, nil
+		}
+	}
+	return ociDescriptor{}, fmt.Errorf("manifest has no layer with media type %s", mediaType)
+}
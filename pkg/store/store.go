@@ -0,0 +1,260 @@
+// Package store turns plugin WASM/manifest bytes into content-addressable
+// blobs on disk, keyed by sha256 digest and referenced through a name->digest
+// index, so a plugin can be installed, upgraded in place, and rolled back to
+// a prior digest without the caller ever touching a filesystem path
+// directly. oci.go builds on Store to pull and push those blobs from an OCI
+// distribution registry.
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Digest is a content hash in "sha256:<hex>" form, the same format OCI
+// manifests and the registry API use, so a Digest round-trips through
+// ParseReference and ociDescriptor.Digest without reformatting.
+type Digest string
+
+// ComputeDigest hashes data and returns its Digest.
+func ComputeDigest(data []byte) Digest {
+	sum := sha256.Sum256(data)
+	return Digest("sha256:" + hex.EncodeToString(sum[:]))
+}
+
+func (d Digest) hex() (string, error) {
+	const prefix = "sha256:"
+	s := string(d)
+	if len(s) <= len(prefix) || s[:len(prefix)] != prefix {
+		return "", fmt.Errorf("unsupported digest %q, want sha256:<hex>", d)
+	}
+	return s[len(prefix):], nil
+}
+
+// revision is one name's history entry: a digest and the order it was set
+// in, so Rollback can report "this was two upgrades ago" if ever needed.
+type revision struct {
+	Digest Digest `json:"digest"`
+}
+
+// indexEntry is one alias's state: Current is what Resolve returns for a
+// bare tag lookup, History holds every digest Current has ever pointed at
+// (oldest first) so Rollback can move Current back without re-pulling.
+type indexEntry struct {
+	Current Digest     `json:"current"`
+	History []revision `json:"history"`
+}
+
+// Store is a content-addressable blob store rooted at <dataDir>/blobs, with
+// a name->digest index persisted alongside it at <dataDir>/index.json.
+type Store struct {
+	blobsDir  string
+	indexPath string
+
+	mu    sync.Mutex
+	index map[string]indexEntry
+}
+
+// NewStore opens (creating if necessary) a Store rooted at dataDir.
+func NewStore(dataDir string) (*Store, error) {
+	blobsDir := filepath.Join(dataDir, "blobs", "sha256")
+	if err := os.MkdirAll(blobsDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create blob directory: %w", err)
+	}
+
+	s := &Store{
+		blobsDir:  blobsDir,
+		indexPath: filepath.Join(dataDir, "index.json"),
+		index:     make(map[string]indexEntry),
+	}
+	if err := s.loadIndex(); err != nil {
+		return nil, fmt.Errorf("load store index: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) loadIndex() error {
+	data, err := os.ReadFile(s.indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.index)
+}
+
+// saveIndex must be called with s.mu held.
+func (s *Store) saveIndex() error {
+	data, err := json.MarshalIndent(s.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.indexPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.indexPath)
+}
+
+func (s *Store) blobPath(d Digest) (string, error) {
+	hexDigest, err := d.hex()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.blobsDir, hexDigest), nil
+}
+
+// PutBlob writes data into the store and returns its Digest. Writing the
+// same content twice is a cheap no-op the second time, since the digest -
+// and therefore the path - is identical.
+func (s *Store) PutBlob(data []byte) (Digest, error) {
+	digest := ComputeDigest(data)
+	path, err := s.blobPath(digest)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return digest, nil
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("write blob: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("install blob: %w", err)
+	}
+	return digest, nil
+}
+
+// GetBlob reads the blob for digest and verifies its content still hashes
+// to digest before returning it - mandatory on every read, not just on
+// pull, so a blob corrupted or swapped out on disk after it was stored is
+// always caught rather than silently loaded.
+func (s *Store) GetBlob(digest Digest) ([]byte, error) {
+	path, err := s.blobPath(digest)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read blob %s: %w", digest, err)
+	}
+	if got := ComputeDigest(data); got != digest {
+		return nil, fmt.Errorf("blob %s failed integrity check: content now hashes to %s, possible tampering", digest, got)
+	}
+	return data, nil
+}
+
+// SetAlias records digest as name's current blob, moving whatever digest
+// name previously pointed at into its history so Rollback can find it
+// again. An alias's first SetAlias call has no previous digest to record.
+func (s *Store) SetAlias(name string, digest Digest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.index[name]
+	if entry.Current != "" && entry.Current != digest {
+		entry.History = append(entry.History, revision{Digest: entry.Current})
+	}
+	entry.Current = digest
+	s.index[name] = entry
+	return s.saveIndex()
+}
+
+// Resolve looks up ref, which is either a bare alias name (resolved via the
+// index to its current digest) or an explicit "name@sha256:<hex>" pin
+// (resolved directly, bypassing the index entirely).
+func (s *Store) Resolve(ref string) (Digest, error) {
+	if name, digest, ok := splitDigestRef(ref); ok {
+		if _, err := s.blobPath(digest); err != nil {
+			return "", err
+		}
+		_ = name
+		return digest, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.index[ref]
+	if !ok || entry.Current == "" {
+		return "", fmt.Errorf("no blob recorded for alias %q", ref)
+	}
+	return entry.Current, nil
+}
+
+// History reports every digest name's Current has ever pointed at,
+// oldest first, ending with the current one - the candidates Rollback
+// accepts.
+func (s *Store) History(name string) []Digest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.index[name]
+	if !ok {
+		return nil
+	}
+	digests := make([]Digest, 0, len(entry.History)+1)
+	for _, rev := range entry.History {
+		digests = append(digests, rev.Digest)
+	}
+	if entry.Current != "" {
+		digests = append(digests, entry.Current)
+	}
+	return digests
+}
+
+// Rollback moves name's current digest back to target, which must already
+// appear in name's History (i.e. be a digest name was previously set to) -
+// Rollback never reaches out to a registry, it only repoints the alias at a
+// blob the store already has on disk.
+func (s *Store) Rollback(name string, target Digest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.index[name]
+	if !ok {
+		return fmt.Errorf("no blob recorded for alias %q", name)
+	}
+	if entry.Current == target {
+		return nil
+	}
+
+	found := false
+	for _, rev := range entry.History {
+		if rev.Digest == target {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("digest %s is not in %s's history", target, name)
+	}
+
+	if _, err := s.blobPath(target); err != nil {
+		return err
+	}
+	entry.History = append(entry.History, revision{Digest: entry.Current})
+	entry.Current = target
+	s.index[name] = entry
+	return s.saveIndex()
+}
+
+// splitDigestRef splits a "name@sha256:<hex>" reference into its name and
+// Digest, reporting false if ref isn't in that form.
+func splitDigestRef(ref string) (name string, digest Digest, ok bool) {
+	for i := 0; i < len(ref); i++ {
+		if ref[i] == '@' {
+			return ref[:i], Digest(ref[i+1:]), true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,76 @@
+package verify
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSHA256HexAndFileSHA256Matches(t *testing.T) {
+	data := []byte("hello world")
+	want := SHA256Hex(data)
+
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if !FileSHA256Matches(path, want) {
+		t.Errorf("FileSHA256Matches = false, want true for matching content")
+	}
+	if FileSHA256Matches(path, "deadbeef") {
+		t.Errorf("FileSHA256Matches = true, want false for a wrong digest")
+	}
+	if FileSHA256Matches(filepath.Join(t.TempDir(), "missing.bin"), want) {
+		t.Errorf("FileSHA256Matches = true for a nonexistent file")
+	}
+}
+
+func TestParseEd25519PublicKeyPEMAndBase64(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	pemStr := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+	got, err := ParseEd25519PublicKey(pemStr)
+	if err != nil {
+		t.Fatalf("ParseEd25519PublicKey(PEM): %v", err)
+	}
+	if !got.Equal(pub) {
+		t.Errorf("PEM round-trip produced a different key")
+	}
+
+	b64 := base64.StdEncoding.EncodeToString(pub)
+	got, err = ParseEd25519PublicKey(b64)
+	if err != nil {
+		t.Fatalf("ParseEd25519PublicKey(base64): %v", err)
+	}
+	if !got.Equal(pub) {
+		t.Errorf("base64 round-trip produced a different key")
+	}
+
+	if _, err := ParseEd25519PublicKey("not a key"); err == nil {
+		t.Errorf("ParseEd25519PublicKey succeeded on garbage input, want error")
+	}
+}
+
+func TestEd25519VerifyAny(t *testing.T) {
+	pubA, privA, _ := ed25519.GenerateKey(nil)
+	pubB, _, _ := ed25519.GenerateKey(nil)
+	message := []byte("plugin-artifact")
+	sig := ed25519.Sign(privA, message)
+
+	if !Ed25519VerifyAny([]ed25519.PublicKey{pubB, pubA}, message, sig) {
+		t.Errorf("Ed25519VerifyAny = false, want true when one of the keys matches")
+	}
+	if Ed25519VerifyAny([]ed25519.PublicKey{pubB}, message, sig) {
+		t.Errorf("Ed25519VerifyAny = true, want false when no key matches")
+	}
+	if Ed25519VerifyAny(nil, message, sig) {
+		t.Errorf("Ed25519VerifyAny = true with no keys configured")
+	}
+}